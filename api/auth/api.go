@@ -15,8 +15,56 @@ type Api interface {
 		accountName string,
 		password EncryptedPassword,
 		deviceDetails DeviceDetails,
+		persistence steamproto.ESessionPersistence,
+		language uint32,
+		qosLevel int32,
 	) (*steamproto.CAuthentication_BeginAuthSessionViaCredentials_Response, error)
-	SubmitSteamGuardCode(ctx context.Context, clientID string, steamID steamid.SteamID, code string) error
+	StartSessionViaQR(
+		ctx context.Context,
+		deviceDetails DeviceDetails,
+	) (*steamproto.CAuthentication_BeginAuthSessionViaQR_Response, error)
+	SubmitSteamGuardCode(
+		ctx context.Context,
+		clientID uint64,
+		steamID steamid.SteamID,
+		code string,
+		codeType GuardType,
+	) error
 	PollSessionStatus(ctx context.Context, clientID string, requestID string) (PollSessionStatusResponse, error)
+	GetAuthSessionsForAccount(ctx context.Context, accessToken string) ([]uint64, error)
+	GetAuthSessionInfo(
+		ctx context.Context,
+		accessToken string,
+		clientID uint64,
+	) (*steamproto.CAuthentication_GetAuthSessionInfo_Response, error)
+	UpdateAuthSessionWithMobileConfirmation(
+		ctx context.Context,
+		accessToken string,
+		version int32,
+		clientID uint64,
+		steamID steamid.SteamID,
+		signature []byte,
+		confirm bool,
+		persistence steamproto.ESessionPersistence,
+	) error
+	EnumerateRefreshTokens(
+		ctx context.Context,
+		accessToken string,
+		includeRevoked bool,
+	) (*steamproto.CAuthentication_RefreshToken_Enumerate_Response, error)
+	RevokeToken(
+		ctx context.Context,
+		accessToken string,
+		token string,
+		revokeAction steamproto.EAuthTokenRevokeAction,
+	) error
+	RevokeRefreshToken(
+		ctx context.Context,
+		accessToken string,
+		tokenID uint64,
+		steamID steamid.SteamID,
+		revokeAction steamproto.EAuthTokenRevokeAction,
+		signature []byte,
+	) error
 	GenerateAccessTokenForApp(ctx context.Context, refreshToken string, renew bool) (GenerateAccessTokenResponse, error)
 }
@@ -26,6 +26,11 @@ type Client struct {
 	Transport api.Transport
 }
 
+// send sends the request through the Transport, mapping authentication EResults to the errors in errors.go.
+func (c *Client) send(ctx context.Context, request api.Request, response any) error {
+	return mapResultError(c.Transport.Send(ctx, request, response))
+}
+
 type GetRsaKeyRequest struct {
 	accountName string
 }
@@ -106,7 +111,7 @@ func publicRsaKey(steamResponse *steamproto.CAuthentication_GetPasswordRSAPublic
 func (c *Client) GetPublicRsaKey(ctx context.Context, accountName string) (*PublicRsaKey, error) {
 	request := GetRsaKeyRequest{accountName: accountName}
 	var response steamproto.CAuthentication_GetPasswordRSAPublicKey_Response
-	sendErr := c.Transport.Send(ctx, request, &response)
+	sendErr := c.send(ctx, request, &response)
 	if sendErr != nil {
 		return nil, sendErr
 	}
@@ -128,7 +133,7 @@ func (c *Client) EncryptAccountPassword(
 ) (EncryptedPassword, error) {
 	publicKey, err := c.GetPublicRsaKey(ctx, accountName)
 	if err != nil {
-		return EncryptedPassword{}, eris.Errorf("GetPublicRsaKey failed: %v", err)
+		return EncryptedPassword{}, eris.Wrap(err, "GetPublicRsaKey failed")
 	}
 
 	encryptedPassword, err := rsa.EncryptPKCS1v15(rand.Reader, &publicKey.PublicKey, []byte(password))
@@ -187,6 +192,8 @@ const (
 const (
 	AndroidUnknownOsType    int32 = -500
 	DefaultGamingDeviceType       = 528
+	// DefaultQosLevel is the qos_level the Steam mobile app sends when starting an auth session
+	DefaultQosLevel int32 = 2
 )
 
 type DeviceDetails struct {
@@ -286,10 +293,14 @@ func (r StartSessionRequest) Values() (url.Values, error) {
 		RememberLogin:       &rememberLogin,
 		Persistence:         &r.Persistence,
 		WebsiteId:           &websiteId,
-		Language:            proto.Uint32(0),
+		PlatformType:        &r.DeviceDetails.PlatformType,
+		Language:            &r.Language,
+		QosLevel:            &r.QosLevel,
 		DeviceDetails: &steamproto.CAuthentication_DeviceDetails{
 			DeviceFriendlyName: &r.DeviceDetails.FriendlyName,
 			PlatformType:       &r.DeviceDetails.PlatformType,
+			OsType:             &r.DeviceDetails.OsType,
+			GamingDeviceType:   &r.DeviceDetails.GamingDeviceType,
 		},
 	})
 }
@@ -319,17 +330,98 @@ func (c *Client) StartSessionWithCredentials(
 	accountName string,
 	password EncryptedPassword,
 	deviceDetails DeviceDetails,
+	persistence steamproto.ESessionPersistence,
+	language uint32,
+	qosLevel int32,
 ) (*steamproto.CAuthentication_BeginAuthSessionViaCredentials_Response, error) {
 	request := StartSessionRequest{
 		AccountName:         accountName,
 		EncryptedPassword:   password.Base64,
 		EncryptionTimestamp: password.TimeStamp,
+		Persistence:         persistence,
 		DeviceDetails:       deviceDetails,
-		Language:            0,
-		QosLevel:            2,
+		Language:            language,
+		QosLevel:            qosLevel,
 	}
 	var response steamproto.CAuthentication_BeginAuthSessionViaCredentials_Response
-	sendErr := c.Transport.Send(ctx, request, &response)
+	sendErr := c.send(ctx, request, &response)
+	if sendErr != nil {
+		return nil, sendErr
+	}
+
+	return &response, nil
+}
+
+type StartSessionViaQRRequest struct {
+	DeviceDetails DeviceDetails
+}
+
+func (r StartSessionViaQRRequest) CacheTTL() time.Duration {
+	return 0
+}
+
+func (r StartSessionViaQRRequest) EnsureResponseSuccess(httpResponse *http.Response) error {
+	return steamlang.EnsureSuccessResponse(httpResponse)
+}
+
+func (r StartSessionViaQRRequest) Headers() (http.Header, error) {
+	return nil, nil
+}
+
+func (r StartSessionViaQRRequest) Retryable() bool {
+	return false
+}
+
+func (r StartSessionViaQRRequest) RequiresApiKey() bool {
+	return false
+}
+
+func (r StartSessionViaQRRequest) Method() string {
+	return http.MethodPost
+}
+
+func (r StartSessionViaQRRequest) OldValues() (url.Values, error) {
+	deviceDetailsBytes, err := json.Marshal(r.DeviceDetails)
+	if err != nil {
+		return nil, eris.Errorf("json marshal failed %v", err)
+	}
+
+	values := make(url.Values)
+	values.Add("device_friendly_name", r.DeviceDetails.FriendlyName)
+	values.Add("platform_type", strconv.Itoa(int(r.DeviceDetails.PlatformType)))
+	values.Add("device_details", string(deviceDetailsBytes))
+	return values, nil
+}
+
+func (r StartSessionViaQRRequest) Values() (url.Values, error) {
+	return api.MarshalSteamEncodedValues(&steamproto.CAuthentication_BeginAuthSessionViaQR_Request{
+		DeviceFriendlyName: &r.DeviceDetails.FriendlyName,
+		PlatformType:       &r.DeviceDetails.PlatformType,
+		DeviceDetails: &steamproto.CAuthentication_DeviceDetails{
+			DeviceFriendlyName: &r.DeviceDetails.FriendlyName,
+			PlatformType:       &r.DeviceDetails.PlatformType,
+			OsType:             &r.DeviceDetails.OsType,
+			GamingDeviceType:   &r.DeviceDetails.GamingDeviceType,
+		},
+	})
+}
+
+func (r StartSessionViaQRRequest) Url() string {
+	return fmt.Sprintf("%v/IAuthenticationService/BeginAuthSessionViaQR/v1/", api.BaseURL)
+}
+
+// StartSessionViaQR
+// Begins an auth session that is approved by scanning the returned ChallengeUrl as a QR code in the Steam mobile
+// app. The session must then be polled with PollSessionStatus until it is approved.
+func (c *Client) StartSessionViaQR(
+	ctx context.Context,
+	deviceDetails DeviceDetails,
+) (*steamproto.CAuthentication_BeginAuthSessionViaQR_Response, error) {
+	request := StartSessionViaQRRequest{
+		DeviceDetails: deviceDetails,
+	}
+	var response steamproto.CAuthentication_BeginAuthSessionViaQR_Response
+	sendErr := c.send(ctx, request, &response)
 	if sendErr != nil {
 		return nil, sendErr
 	}
@@ -390,18 +482,25 @@ func (c *Client) SubmitSteamGuardCode(
 	clientID uint64,
 	steamID steamid.SteamID,
 	code string,
+	codeType GuardType,
 ) error {
 	if !steamID.IsValidIndividual() {
 		return eris.Errorf("steamID is not valid individual: %v", steamID.String())
 	}
 
+	switch codeType {
+	case EmailCodeGuardType, DeviceCodeGuardType:
+	default:
+		return eris.Errorf("guard type %v does not accept a code", codeType)
+	}
+
 	request := UpdateSessionWithSteamGuardCodeRequest{
 		ClientID: clientID,
 		SteamID:  steamID.String(),
 		Code:     code,
-		CodeType: DeviceCodeGuardType,
+		CodeType: codeType,
 	}
-	sendErr := c.Transport.Send(ctx, request, nil)
+	sendErr := c.send(ctx, request, nil)
 	if sendErr != nil {
 		return sendErr
 	}
@@ -477,7 +576,7 @@ func (c *Client) PollSessionStatus(
 		RequestID: requestID,
 	}
 	var response steamproto.CAuthentication_PollAuthSessionStatus_Response
-	sendErr := c.Transport.Send(ctx, request, &response)
+	sendErr := c.send(ctx, request, &response)
 	if sendErr != nil {
 		return nil, sendErr
 	}
@@ -526,6 +625,7 @@ func (r GenerateAccessTokenRequest) Values() (url.Values, error) {
 	return api.MarshalSteamEncodedValues(&steamproto.CAuthentication_AccessToken_GenerateForApp_Request{
 		RefreshToken: &r.RefreshToken,
 		Steamid:      proto.Uint64(r.SteamID.ID()),
+		RenewalType:  steamproto.ETokenRenewalType(r.RenewalType).Enum(),
 	})
 }
 
@@ -566,10 +666,460 @@ func (c *Client) GenerateAccessTokenForApp(
 		RenewalType:  renewalType,
 	}
 	response := new(steamproto.CAuthentication_AccessToken_GenerateForApp_Response)
-	sendErr := c.Transport.Send(ctx, request, response)
+	sendErr := c.send(ctx, request, response)
 	if sendErr != nil {
 		return nil, sendErr
 	}
 
 	return response, nil
 }
+
+type GetAuthSessionsForAccountRequest struct {
+	AccessToken string
+}
+
+func (r GetAuthSessionsForAccountRequest) CacheTTL() time.Duration {
+	return 0
+}
+
+func (r GetAuthSessionsForAccountRequest) EnsureResponseSuccess(httpResponse *http.Response) error {
+	return steamlang.EnsureSuccessResponse(httpResponse)
+}
+
+func (r GetAuthSessionsForAccountRequest) Headers() (http.Header, error) {
+	return nil, nil
+}
+
+func (r GetAuthSessionsForAccountRequest) Retryable() bool {
+	return true
+}
+
+func (r GetAuthSessionsForAccountRequest) RequiresApiKey() bool {
+	return false
+}
+
+func (r GetAuthSessionsForAccountRequest) Method() string {
+	return http.MethodGet
+}
+
+func (r GetAuthSessionsForAccountRequest) OldValues() (url.Values, error) {
+	return url.Values{}, nil
+}
+
+func (r GetAuthSessionsForAccountRequest) Values() (url.Values, error) {
+	return api.MarshalSteamEncodedValues(&steamproto.CAuthentication_GetAuthSessionsForAccount_Request{})
+}
+
+func (r GetAuthSessionsForAccountRequest) Url() string {
+	return fmt.Sprintf(
+		"%v/IAuthenticationService/GetAuthSessionsForAccount/v1/?access_token=%s",
+		api.BaseURL,
+		url.QueryEscape(r.AccessToken),
+	)
+}
+
+// GetAuthSessionsForAccount
+// Lists the client ids of auth sessions that are waiting for the account's mobile authenticator to approve them.
+func (c *Client) GetAuthSessionsForAccount(ctx context.Context, accessToken string) ([]uint64, error) {
+	request := GetAuthSessionsForAccountRequest{
+		AccessToken: accessToken,
+	}
+	var response steamproto.CAuthentication_GetAuthSessionsForAccount_Response
+	sendErr := c.send(ctx, request, &response)
+	if sendErr != nil {
+		return nil, sendErr
+	}
+
+	return response.GetClientIds(), nil
+}
+
+type GetAuthSessionInfoRequest struct {
+	AccessToken string
+	ClientID    uint64
+}
+
+func (r GetAuthSessionInfoRequest) CacheTTL() time.Duration {
+	return 0
+}
+
+func (r GetAuthSessionInfoRequest) EnsureResponseSuccess(httpResponse *http.Response) error {
+	return steamlang.EnsureSuccessResponse(httpResponse)
+}
+
+func (r GetAuthSessionInfoRequest) Headers() (http.Header, error) {
+	return nil, nil
+}
+
+func (r GetAuthSessionInfoRequest) Retryable() bool {
+	return false
+}
+
+func (r GetAuthSessionInfoRequest) RequiresApiKey() bool {
+	return false
+}
+
+func (r GetAuthSessionInfoRequest) Method() string {
+	return http.MethodPost
+}
+
+func (r GetAuthSessionInfoRequest) OldValues() (url.Values, error) {
+	values := make(url.Values)
+	values.Add("client_id", strconv.FormatUint(r.ClientID, 10))
+	return values, nil
+}
+
+func (r GetAuthSessionInfoRequest) Values() (url.Values, error) {
+	return api.MarshalSteamEncodedValues(&steamproto.CAuthentication_GetAuthSessionInfo_Request{
+		ClientId: &r.ClientID,
+	})
+}
+
+func (r GetAuthSessionInfoRequest) Url() string {
+	return fmt.Sprintf(
+		"%v/IAuthenticationService/GetAuthSessionInfo/v1/?access_token=%s",
+		api.BaseURL,
+		url.QueryEscape(r.AccessToken),
+	)
+}
+
+// GetAuthSessionInfo
+// Retrieves the details of a login attempt, such as where it originated, and the version that must be used when
+// approving or denying it.
+func (c *Client) GetAuthSessionInfo(
+	ctx context.Context,
+	accessToken string,
+	clientID uint64,
+) (*steamproto.CAuthentication_GetAuthSessionInfo_Response, error) {
+	request := GetAuthSessionInfoRequest{
+		AccessToken: accessToken,
+		ClientID:    clientID,
+	}
+	var response steamproto.CAuthentication_GetAuthSessionInfo_Response
+	sendErr := c.send(ctx, request, &response)
+	if sendErr != nil {
+		return nil, sendErr
+	}
+
+	return &response, nil
+}
+
+type UpdateSessionWithMobileConfirmationRequest struct {
+	AccessToken string
+	Version     int32
+	ClientID    uint64
+	SteamID     steamid.SteamID
+	Signature   []byte
+	Confirm     bool
+	Persistence steamproto.ESessionPersistence
+}
+
+func (r UpdateSessionWithMobileConfirmationRequest) CacheTTL() time.Duration {
+	return 0
+}
+
+func (r UpdateSessionWithMobileConfirmationRequest) EnsureResponseSuccess(httpResponse *http.Response) error {
+	return steamlang.EnsureSuccessResponse(httpResponse)
+}
+
+func (r UpdateSessionWithMobileConfirmationRequest) Headers() (http.Header, error) {
+	return nil, nil
+}
+
+func (r UpdateSessionWithMobileConfirmationRequest) Retryable() bool {
+	return false
+}
+
+func (r UpdateSessionWithMobileConfirmationRequest) RequiresApiKey() bool {
+	return false
+}
+
+func (r UpdateSessionWithMobileConfirmationRequest) Method() string {
+	return http.MethodPost
+}
+
+func (r UpdateSessionWithMobileConfirmationRequest) OldValues() (url.Values, error) {
+	values := make(url.Values)
+	values.Add("version", strconv.Itoa(int(r.Version)))
+	values.Add("client_id", strconv.FormatUint(r.ClientID, 10))
+	values.Add("steamid", r.SteamID.String())
+	values.Add("signature", base64.StdEncoding.EncodeToString(r.Signature))
+	values.Add("confirm", strconv.FormatBool(r.Confirm))
+	values.Add("persistence", strconv.Itoa(int(r.Persistence)))
+	return values, nil
+}
+
+func (r UpdateSessionWithMobileConfirmationRequest) Values() (url.Values, error) {
+	return api.MarshalSteamEncodedValues(&steamproto.CAuthentication_UpdateAuthSessionWithMobileConfirmation_Request{
+		Version:     &r.Version,
+		ClientId:    &r.ClientID,
+		Steamid:     proto.Uint64(r.SteamID.ID()),
+		Signature:   r.Signature,
+		Confirm:     &r.Confirm,
+		Persistence: &r.Persistence,
+	})
+}
+
+func (r UpdateSessionWithMobileConfirmationRequest) Url() string {
+	return fmt.Sprintf(
+		"%v/IAuthenticationService/UpdateAuthSessionWithMobileConfirmation/v1/?access_token=%s",
+		api.BaseURL,
+		url.QueryEscape(r.AccessToken),
+	)
+}
+
+// UpdateAuthSessionWithMobileConfirmation
+// Approves or denies a login attempt as the account's mobile authenticator. signature must be generated from the
+// shared secret, see totp.State.GenerateSessionSignature.
+func (c *Client) UpdateAuthSessionWithMobileConfirmation(
+	ctx context.Context,
+	accessToken string,
+	version int32,
+	clientID uint64,
+	steamID steamid.SteamID,
+	signature []byte,
+	confirm bool,
+	persistence steamproto.ESessionPersistence,
+) error {
+	request := UpdateSessionWithMobileConfirmationRequest{
+		AccessToken: accessToken,
+		Version:     version,
+		ClientID:    clientID,
+		SteamID:     steamID,
+		Signature:   signature,
+		Confirm:     confirm,
+		Persistence: persistence,
+	}
+	sendErr := c.send(ctx, request, nil)
+	if sendErr != nil {
+		return sendErr
+	}
+
+	return nil
+}
+
+type EnumerateRefreshTokensRequest struct {
+	AccessToken    string
+	IncludeRevoked bool
+}
+
+func (r EnumerateRefreshTokensRequest) CacheTTL() time.Duration {
+	return 0
+}
+
+func (r EnumerateRefreshTokensRequest) EnsureResponseSuccess(httpResponse *http.Response) error {
+	return steamlang.EnsureSuccessResponse(httpResponse)
+}
+
+func (r EnumerateRefreshTokensRequest) Headers() (http.Header, error) {
+	return nil, nil
+}
+
+func (r EnumerateRefreshTokensRequest) Retryable() bool {
+	return true
+}
+
+func (r EnumerateRefreshTokensRequest) RequiresApiKey() bool {
+	return false
+}
+
+func (r EnumerateRefreshTokensRequest) Method() string {
+	return http.MethodGet
+}
+
+func (r EnumerateRefreshTokensRequest) OldValues() (url.Values, error) {
+	values := make(url.Values)
+	values.Add("include_revoked", strconv.FormatBool(r.IncludeRevoked))
+	return values, nil
+}
+
+func (r EnumerateRefreshTokensRequest) Values() (url.Values, error) {
+	return api.MarshalSteamEncodedValues(&steamproto.CAuthentication_RefreshToken_Enumerate_Request{
+		IncludeRevoked: &r.IncludeRevoked,
+	})
+}
+
+func (r EnumerateRefreshTokensRequest) Url() string {
+	return fmt.Sprintf(
+		"%v/IAuthenticationService/EnumerateTokens/v1/?access_token=%s",
+		api.BaseURL,
+		url.QueryEscape(r.AccessToken),
+	)
+}
+
+// EnumerateRefreshTokens
+// Lists the refresh tokens issued for the account. RequestingToken in the response identifies the refresh token
+// that accessToken was generated from.
+func (c *Client) EnumerateRefreshTokens(
+	ctx context.Context,
+	accessToken string,
+	includeRevoked bool,
+) (*steamproto.CAuthentication_RefreshToken_Enumerate_Response, error) {
+	request := EnumerateRefreshTokensRequest{
+		AccessToken:    accessToken,
+		IncludeRevoked: includeRevoked,
+	}
+	var response steamproto.CAuthentication_RefreshToken_Enumerate_Response
+	sendErr := c.send(ctx, request, &response)
+	if sendErr != nil {
+		return nil, sendErr
+	}
+
+	return &response, nil
+}
+
+type RevokeTokenRequest struct {
+	AccessToken  string
+	Token        string
+	RevokeAction steamproto.EAuthTokenRevokeAction
+}
+
+func (r RevokeTokenRequest) CacheTTL() time.Duration {
+	return 0
+}
+
+func (r RevokeTokenRequest) EnsureResponseSuccess(httpResponse *http.Response) error {
+	return steamlang.EnsureSuccessResponse(httpResponse)
+}
+
+func (r RevokeTokenRequest) Headers() (http.Header, error) {
+	return nil, nil
+}
+
+func (r RevokeTokenRequest) Retryable() bool {
+	return false
+}
+
+func (r RevokeTokenRequest) RequiresApiKey() bool {
+	return false
+}
+
+func (r RevokeTokenRequest) Method() string {
+	return http.MethodPost
+}
+
+func (r RevokeTokenRequest) OldValues() (url.Values, error) {
+	values := make(url.Values)
+	values.Add("token", r.Token)
+	values.Add("revoke_action", strconv.Itoa(int(r.RevokeAction)))
+	return values, nil
+}
+
+func (r RevokeTokenRequest) Values() (url.Values, error) {
+	return api.MarshalSteamEncodedValues(&steamproto.CAuthentication_Token_Revoke_Request{
+		Token:        &r.Token,
+		RevokeAction: &r.RevokeAction,
+	})
+}
+
+func (r RevokeTokenRequest) Url() string {
+	return fmt.Sprintf(
+		"%v/IAuthenticationService/RevokeToken/v1/?access_token=%s",
+		api.BaseURL,
+		url.QueryEscape(r.AccessToken),
+	)
+}
+
+// RevokeToken
+// Revokes the given refresh token, e.g. to log out of the session it belongs to.
+func (c *Client) RevokeToken(
+	ctx context.Context,
+	accessToken string,
+	token string,
+	revokeAction steamproto.EAuthTokenRevokeAction,
+) error {
+	request := RevokeTokenRequest{
+		AccessToken:  accessToken,
+		Token:        token,
+		RevokeAction: revokeAction,
+	}
+	sendErr := c.send(ctx, request, nil)
+	if sendErr != nil {
+		return sendErr
+	}
+
+	return nil
+}
+
+type RevokeRefreshTokenRequest struct {
+	AccessToken  string
+	TokenID      uint64
+	SteamID      steamid.SteamID
+	RevokeAction steamproto.EAuthTokenRevokeAction
+	Signature    []byte
+}
+
+func (r RevokeRefreshTokenRequest) CacheTTL() time.Duration {
+	return 0
+}
+
+func (r RevokeRefreshTokenRequest) EnsureResponseSuccess(httpResponse *http.Response) error {
+	return steamlang.EnsureSuccessResponse(httpResponse)
+}
+
+func (r RevokeRefreshTokenRequest) Headers() (http.Header, error) {
+	return nil, nil
+}
+
+func (r RevokeRefreshTokenRequest) Retryable() bool {
+	return false
+}
+
+func (r RevokeRefreshTokenRequest) RequiresApiKey() bool {
+	return false
+}
+
+func (r RevokeRefreshTokenRequest) Method() string {
+	return http.MethodPost
+}
+
+func (r RevokeRefreshTokenRequest) OldValues() (url.Values, error) {
+	values := make(url.Values)
+	values.Add("token_id", strconv.FormatUint(r.TokenID, 10))
+	values.Add("steamid", r.SteamID.String())
+	values.Add("revoke_action", strconv.Itoa(int(r.RevokeAction)))
+	values.Add("signature", base64.StdEncoding.EncodeToString(r.Signature))
+	return values, nil
+}
+
+func (r RevokeRefreshTokenRequest) Values() (url.Values, error) {
+	return api.MarshalSteamEncodedValues(&steamproto.CAuthentication_RefreshToken_Revoke_Request{
+		TokenId:      &r.TokenID,
+		Steamid:      proto.Uint64(r.SteamID.ID()),
+		RevokeAction: &r.RevokeAction,
+		Signature:    r.Signature,
+	})
+}
+
+func (r RevokeRefreshTokenRequest) Url() string {
+	return fmt.Sprintf(
+		"%v/IAuthenticationService/RevokeRefreshToken/v1/?access_token=%s",
+		api.BaseURL,
+		url.QueryEscape(r.AccessToken),
+	)
+}
+
+// RevokeRefreshToken
+// Revokes one of the account's refresh tokens by its id, as listed by EnumerateRefreshTokens. Revoking tokens other
+// than the requesting token must be signed with the shared secret, see totp.State.GenerateTokenRevokeSignature.
+func (c *Client) RevokeRefreshToken(
+	ctx context.Context,
+	accessToken string,
+	tokenID uint64,
+	steamID steamid.SteamID,
+	revokeAction steamproto.EAuthTokenRevokeAction,
+	signature []byte,
+) error {
+	request := RevokeRefreshTokenRequest{
+		AccessToken:  accessToken,
+		TokenID:      tokenID,
+		SteamID:      steamID,
+		RevokeAction: revokeAction,
+		Signature:    signature,
+	}
+	sendErr := c.send(ctx, request, nil)
+	if sendErr != nil {
+		return sendErr
+	}
+
+	return nil
+}
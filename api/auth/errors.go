@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/escrow-tf/steam/steamlang"
+)
+
+var (
+	InvalidPasswordError            = errors.New("the account name or password is incorrect")
+	RateLimitExceededError          = errors.New("too many login attempts have been made recently; wait before trying again")
+	AccountLoginDeniedThrottleError = errors.New("logins for this account are being throttled after too many failed attempts")
+	TwoFactorCodeMismatchError      = errors.New("the Steam Guard mobile code was rejected; the clock used to generate it may be out of sync")
+	InvalidLoginAuthCodeError       = errors.New("the Steam Guard email code was rejected")
+	ExpiredError                    = errors.New("the auth session or token has expired")
+)
+
+// mapResultError associates the EResult errors that matter for authentication with one of the errors above, so
+// callers can match them with errors.Is. The original *steamlang.ResultError is still available with errors.As.
+func mapResultError(err error) error {
+	var resultErr *steamlang.ResultError
+	if !errors.As(err, &resultErr) {
+		return err
+	}
+
+	var authErr error
+	switch resultErr.Result {
+	case steamlang.InvalidPasswordResult:
+		authErr = InvalidPasswordError
+	case steamlang.RateLimitExceededResult:
+		authErr = RateLimitExceededError
+	case steamlang.AccountLoginDeniedThrottleResult:
+		authErr = AccountLoginDeniedThrottleError
+	case steamlang.TwoFactorCodeMismatchResult:
+		authErr = TwoFactorCodeMismatchError
+	case steamlang.InvalidLoginAuthCodeResult:
+		authErr = InvalidLoginAuthCodeError
+	case steamlang.ExpiredResult:
+		authErr = ExpiredError
+	default:
+		return err
+	}
+
+	return fmt.Errorf("%w: %w", authErr, err)
+}
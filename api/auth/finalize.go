@@ -0,0 +1,172 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/escrow-tf/steam/steamid"
+	"github.com/escrow-tf/steam/steamlang"
+)
+
+const LoginBaseURL = "https://login.steampowered.com"
+
+type FinalizeLoginRequest struct {
+	RefreshToken string
+	SessionId    string
+	Redirect     string
+}
+
+func (r FinalizeLoginRequest) CacheTTL() time.Duration {
+	return 0
+}
+
+func (r FinalizeLoginRequest) EnsureResponseSuccess(httpResponse *http.Response) error {
+	return steamlang.EnsureSuccessResponse(httpResponse)
+}
+
+func (r FinalizeLoginRequest) Headers() (http.Header, error) {
+	return http.Header{
+		"Origin":  []string{"https://steamcommunity.com"},
+		"Referer": []string{"https://steamcommunity.com/"},
+	}, nil
+}
+
+func (r FinalizeLoginRequest) Retryable() bool {
+	return false
+}
+
+func (r FinalizeLoginRequest) RequiresApiKey() bool {
+	return false
+}
+
+func (r FinalizeLoginRequest) Method() string {
+	return http.MethodPost
+}
+
+func (r FinalizeLoginRequest) OldValues() (url.Values, error) {
+	values := make(url.Values)
+	values.Add("nonce", r.RefreshToken)
+	values.Add("sessionid", r.SessionId)
+	values.Add("redir", r.Redirect)
+	return values, nil
+}
+
+func (r FinalizeLoginRequest) Values() (url.Values, error) {
+	return r.OldValues()
+}
+
+func (r FinalizeLoginRequest) Url() string {
+	return LoginBaseURL + "/jwt/finalizelogin"
+}
+
+type TransferInfo struct {
+	Url    string            `json:"url"`
+	Params map[string]string `json:"params"`
+}
+
+type FinalizeLoginResponse struct {
+	SteamId       string         `json:"steamID"`
+	Redirect      string         `json:"redir"`
+	TransferInfo  []TransferInfo `json:"transfer_info"`
+	PrimaryDomain string         `json:"primary_domain"`
+	Error         int            `json:"error,omitempty"`
+}
+
+// FinalizeLogin
+// Exchanges a refresh token for the list of transfers that set the steamLoginSecure cookie on each Steam site.
+// Each transfer must then be completed with TransferLogin.
+func (c *Client) FinalizeLogin(
+	ctx context.Context,
+	refreshToken string,
+	sessionId string,
+) (*FinalizeLoginResponse, error) {
+	request := FinalizeLoginRequest{
+		RefreshToken: refreshToken,
+		SessionId:    sessionId,
+		Redirect:     "https://steamcommunity.com/login/home/?goto=",
+	}
+	var response FinalizeLoginResponse
+	sendErr := c.send(ctx, request, &response)
+	if sendErr != nil {
+		return nil, sendErr
+	}
+
+	if response.Error != 0 {
+		return nil, mapResultError(steamlang.EResultError(steamlang.EResult(response.Error)))
+	}
+
+	return &response, nil
+}
+
+type TransferLoginRequest struct {
+	TransferInfo TransferInfo
+	SteamID      steamid.SteamID
+}
+
+func (r TransferLoginRequest) CacheTTL() time.Duration {
+	return 0
+}
+
+func (r TransferLoginRequest) EnsureResponseSuccess(httpResponse *http.Response) error {
+	return steamlang.EnsureSuccessResponse(httpResponse)
+}
+
+func (r TransferLoginRequest) Headers() (http.Header, error) {
+	return nil, nil
+}
+
+func (r TransferLoginRequest) Retryable() bool {
+	return false
+}
+
+func (r TransferLoginRequest) RequiresApiKey() bool {
+	return false
+}
+
+func (r TransferLoginRequest) Method() string {
+	return http.MethodPost
+}
+
+func (r TransferLoginRequest) OldValues() (url.Values, error) {
+	values := make(url.Values)
+	for key, value := range r.TransferInfo.Params {
+		values.Add(key, value)
+	}
+	values.Add("steamID", r.SteamID.String())
+	return values, nil
+}
+
+func (r TransferLoginRequest) Values() (url.Values, error) {
+	return r.OldValues()
+}
+
+func (r TransferLoginRequest) Url() string {
+	return r.TransferInfo.Url
+}
+
+type TransferLoginResponse struct {
+	Result steamlang.EResult `json:"result"`
+}
+
+// TransferLogin
+// Completes one of the transfers returned by FinalizeLogin. The transfer's site responds by setting its
+// steamLoginSecure cookie, which is stored in the transport's cookie jar.
+func (c *Client) TransferLogin(ctx context.Context, transferInfo TransferInfo, steamID steamid.SteamID) error {
+	request := TransferLoginRequest{
+		TransferInfo: transferInfo,
+		SteamID:      steamID,
+	}
+	var response TransferLoginResponse
+	sendErr := c.send(ctx, request, &response)
+	if sendErr != nil {
+		return sendErr
+	}
+
+	if response.Result != steamlang.OKResult {
+		return mapResultError(steamlang.EResultError(response.Result))
+	}
+
+	return nil
+}
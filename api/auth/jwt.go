@@ -20,9 +20,8 @@ func DecodeSimpleJwt(jwtString string) (RefreshJwt, error) {
 		return RefreshJwt{}, eris.Errorf("expected 3 parts in JWT, got %d", len(parts))
 	}
 
-	standardBase64 := strings.ReplaceAll(parts[1], "-", "+")
-	standardBase64 = strings.ReplaceAll(standardBase64, "_", "/")
-	decoded, err := base64.StdEncoding.DecodeString(standardBase64)
+	// JWT segments are unpadded base64url
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
 	if err != nil {
 		return RefreshJwt{}, eris.Errorf("base64 decoding failed: %v", err)
 	}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"sync"
+)
+
+// recordingJar is a cookiejar.Jar that remembers the attributes of the cookies it was given, which the Jar itself
+// doesn't return from Cookies.
+type recordingJar struct {
+	*cookiejar.Jar
+
+	mutex sync.Mutex
+	// cookies holds the last cookie set for each name, by the host it was set for
+	cookies map[string]map[string]*http.Cookie
+}
+
+func newRecordingJar() (*recordingJar, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &recordingJar{
+		Jar:     jar,
+		cookies: make(map[string]map[string]*http.Cookie),
+	}, nil
+}
+
+func (j *recordingJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.Jar.SetCookies(u, cookies)
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	hostCookies := j.cookies[u.Hostname()]
+	if hostCookies == nil {
+		hostCookies = make(map[string]*http.Cookie)
+		j.cookies[u.Hostname()] = hostCookies
+	}
+
+	for _, cookie := range cookies {
+		recorded := *cookie
+		hostCookies[cookie.Name] = &recorded
+	}
+}
+
+// fullCookies returns the cookies that Cookies returns for u, with the attributes they were set with. Cookies that
+// were set for another host, such as a parent domain, are matched by name and value.
+func (j *recordingJar) fullCookies(u *url.URL) []*http.Cookie {
+	cookies := j.Jar.Cookies(u)
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	for i, cookie := range cookies {
+		recorded, found := j.cookies[u.Hostname()][cookie.Name]
+		if !found || recorded.Value != cookie.Value {
+			recorded, found = j.findRecorded(cookie)
+		}
+
+		if found {
+			fullCookie := *recorded
+			cookies[i] = &fullCookie
+		}
+	}
+
+	return cookies
+}
+
+func (j *recordingJar) findRecorded(cookie *http.Cookie) (*http.Cookie, bool) {
+	for _, hostCookies := range j.cookies {
+		recorded, found := hostCookies[cookie.Name]
+		if found && recorded.Value == cookie.Value {
+			return recorded, true
+		}
+	}
+
+	return nil, false
+}
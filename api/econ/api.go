@@ -9,4 +9,9 @@ type Api interface {
 		getSent, getReceived, getDescriptions, activeOnly, historicalOnly bool,
 		historicalCutoff uint32,
 	) (*GetTradeOffersResponse, error)
+	GetAssetClassInfo(
+		ctx context.Context,
+		appId uint32,
+		classes []ClassInstance,
+	) (map[ClassInstance]AssetClassInfo, error)
 }
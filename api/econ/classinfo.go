@@ -0,0 +1,145 @@
+package econ
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/escrow-tf/steam/api"
+	"github.com/escrow-tf/steam/steamlang"
+	"github.com/rotisserie/eris"
+)
+
+// ClassInstance identifies the description of an item by its classid and instanceid
+type ClassInstance struct {
+	ClassId    uint64
+	InstanceId uint64
+}
+
+type AssetClassInfo struct {
+	ClassId         string `json:"classid"`
+	IconUrl         string `json:"icon_url"`
+	Name            string `json:"name"`
+	MarketName      string `json:"market_name"`
+	MarketHashName  string `json:"market_hash_name"`
+	NameColor       string `json:"name_color"`
+	BackgroundColor string `json:"background_color"`
+	Type            string `json:"type"`
+}
+
+type GetAssetClassInfoRequest struct {
+	appId    uint32
+	classes  []ClassInstance
+	language string
+}
+
+func (g GetAssetClassInfoRequest) CacheTTL() time.Duration {
+	return 0
+}
+
+func (g GetAssetClassInfoRequest) EnsureResponseSuccess(httpResponse *http.Response) error {
+	return steamlang.EnsureSuccessResponse(httpResponse)
+}
+
+func (g GetAssetClassInfoRequest) Headers() (http.Header, error) {
+	return nil, nil
+}
+
+func (g GetAssetClassInfoRequest) Retryable() bool {
+	return true
+}
+
+func (g GetAssetClassInfoRequest) RequiresApiKey() bool {
+	return true
+}
+
+func (g GetAssetClassInfoRequest) Method() string {
+	return http.MethodGet
+}
+
+func (g GetAssetClassInfoRequest) Url() string {
+	return fmt.Sprintf("%s/ISteamEconomy/GetAssetClassInfo/v1/", api.BaseURL)
+}
+
+func (g GetAssetClassInfoRequest) OldValues() (url.Values, error) {
+	values := make(url.Values)
+	values.Add("appid", strconv.FormatUint(uint64(g.appId), 10))
+	values.Add("language", g.language)
+	values.Add("class_count", strconv.Itoa(len(g.classes)))
+	for i, class := range g.classes {
+		values.Add(fmt.Sprintf("classid%d", i), strconv.FormatUint(class.ClassId, 10))
+		values.Add(fmt.Sprintf("instanceid%d", i), strconv.FormatUint(class.InstanceId, 10))
+	}
+	return values, nil
+}
+
+func (g GetAssetClassInfoRequest) Values() (url.Values, error) {
+	return g.OldValues()
+}
+
+// GetAssetClassInfoResponse holds one entry per class, keyed by "<classid>" or "<classid>_<instanceid>", alongside a
+// "success" entry
+type GetAssetClassInfoResponse struct {
+	Result map[string]json.RawMessage `json:"result"`
+}
+
+// GetAssetClassInfo fetches the descriptions of the given classes of an app's items.
+func (c *Client) GetAssetClassInfo(
+	ctx context.Context,
+	appId uint32,
+	classes []ClassInstance,
+) (map[ClassInstance]AssetClassInfo, error) {
+	request := GetAssetClassInfoRequest{
+		appId:    appId,
+		classes:  classes,
+		language: "en_us",
+	}
+	var response GetAssetClassInfoResponse
+	sendErr := c.Transport.Send(ctx, request, &response)
+	if sendErr != nil {
+		return nil, sendErr
+	}
+
+	var success bool
+	successErr := json.Unmarshal(response.Result["success"], &success)
+	if successErr != nil || !success {
+		return nil, eris.Errorf("GetAssetClassInfo was unsuccessful: %s", response.Result["error"])
+	}
+
+	classInfos := make(map[ClassInstance]AssetClassInfo, len(classes))
+	for key, rawClassInfo := range response.Result {
+		if key == "success" || key == "error" {
+			continue
+		}
+
+		classIdString, instanceIdString, _ := strings.Cut(key, "_")
+		var class ClassInstance
+		var err error
+		class.ClassId, err = strconv.ParseUint(classIdString, 10, 64)
+		if err != nil {
+			return nil, eris.Errorf("GetAssetClassInfo returned invalid classid %q: %v", key, err)
+		}
+
+		if len(instanceIdString) != 0 {
+			class.InstanceId, err = strconv.ParseUint(instanceIdString, 10, 64)
+			if err != nil {
+				return nil, eris.Errorf("GetAssetClassInfo returned invalid instanceid %q: %v", key, err)
+			}
+		}
+
+		var classInfo AssetClassInfo
+		err = json.Unmarshal(rawClassInfo, &classInfo)
+		if err != nil {
+			return nil, eris.Errorf("GetAssetClassInfo returned invalid class info for %q: %v", key, err)
+		}
+
+		classInfos[class] = classInfo
+	}
+
+	return classInfos, nil
+}
@@ -0,0 +1,7 @@
+package api
+
+import "errors"
+
+// MissingApiKeyError is returned when sending a request that needs a Web API key without
+// HttpTransportOptions.WebApiKey
+var MissingApiKeyError = errors.New("this request requires a Web API key, but none was configured")
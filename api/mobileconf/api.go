@@ -1,11 +1,20 @@
 package mobileconf
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Api interface {
 	SendMobileConfRequest(ctx context.Context, request Request, response any) error
 	GetList(ctx context.Context) (GetListResponse, error)
 	GetDetailsPage(ctx context.Context, id string) (DetailsPageResponse, error)
+	GetConfirmationDetails(ctx context.Context, confirmation Confirmation) (DetailsPageResponse, error)
 	Accept(ctx context.Context, id, nonce string) (AcceptResponse, error)
 	Decline(ctx context.Context, id, nonce string) (DeclineResponse, error)
+	AcceptMany(ctx context.Context, confirmations []Confirmation) ([]BatchResult, error)
+	DeclineMany(ctx context.Context, confirmations []Confirmation) ([]BatchResult, error)
+	ConfirmTradeOffer(ctx context.Context, offerID uint64) error
+	CancelTradeOffer(ctx context.Context, offerID uint64) error
+	Watch(ctx context.Context, interval time.Duration) <-chan ConfirmationEvent
 }
@@ -0,0 +1,101 @@
+package mobileconf
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rotisserie/eris"
+)
+
+// MaxBatchSize is the most confirmations AcceptMany and DeclineMany send in a single multiajaxop request
+const MaxBatchSize = 25
+
+type BatchResponse struct {
+	Success   bool   `json:"success"`
+	NeedsAuth bool   `json:"needsauth,omitempty"`
+	Message   string `json:"message,omitempty"`
+	Details   string `json:"details,omitempty"`
+}
+
+func (r *BatchResponse) needsAuth() bool {
+	return r.NeedsAuth
+}
+
+// BatchResult is the outcome of one multiajaxop request. Err is set when the request failed or Steam reported that
+// it was unsuccessful, in which case none of its confirmations should be assumed to have been handled.
+type BatchResult struct {
+	Confirmations []Confirmation
+	Response      BatchResponse
+	Err           error
+}
+
+// AcceptMany accepts the confirmations using multiajaxop, splitting them into batches of at most MaxBatchSize.
+// Every batch is attempted; the returned error joins the errors of the batches that failed.
+func (c *Client) AcceptMany(ctx context.Context, confirmations []Confirmation) ([]BatchResult, error) {
+	return c.sendBatches(ctx, confirmations, "allow", "accept")
+}
+
+// DeclineMany declines the confirmations using multiajaxop, splitting them into batches of at most MaxBatchSize.
+// Every batch is attempted; the returned error joins the errors of the batches that failed.
+func (c *Client) DeclineMany(ctx context.Context, confirmations []Confirmation) ([]BatchResult, error) {
+	return c.sendBatches(ctx, confirmations, "cancel", "reject")
+}
+
+func (c *Client) sendBatches(
+	ctx context.Context,
+	confirmations []Confirmation,
+	operation string,
+	tag string,
+) ([]BatchResult, error) {
+	var results []BatchResult
+	var errs []error
+	for start := 0; start < len(confirmations); start += MaxBatchSize {
+		batch := confirmations[start:min(start+MaxBatchSize, len(confirmations))]
+		result := BatchResult{
+			Confirmations: batch,
+		}
+
+		result.Response, result.Err = c.sendBatch(ctx, batch, operation, tag)
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, errors.Join(errs...)
+}
+
+func (c *Client) sendBatch(
+	ctx context.Context,
+	batch []Confirmation,
+	operation string,
+	tag string,
+) (BatchResponse, error) {
+	batchOperation := &BatchOperation{
+		Operation: operation,
+	}
+	for _, confirmation := range batch {
+		batchOperation.IDs = append(batchOperation.IDs, confirmation.ID)
+		batchOperation.Nonces = append(batchOperation.Nonces, confirmation.Nonce)
+	}
+
+	request := Request{
+		Posts:          true,
+		Path:           "multiajaxop",
+		Tag:            tag,
+		BatchOperation: batchOperation,
+	}
+
+	response := BatchResponse{}
+	err := c.SendMobileConfRequest(ctx, request, &response)
+	if err != nil {
+		return BatchResponse{}, eris.Wrap(err, "multiajaxop mobile conf request failed")
+	}
+
+	if !response.Success {
+		return response, eris.Errorf("multiajaxop mobile conf request failed: %v", response.Message)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,119 @@
+package mobileconf
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/escrow-tf/steam/api"
+	"github.com/escrow-tf/steam/api/twofactor"
+	"github.com/escrow-tf/steam/steamid"
+	"github.com/escrow-tf/steam/totp"
+)
+
+// fakeTransport answers steam time queries, and records the form values of every mobileconf request it receives
+type fakeTransport struct {
+	values []url.Values
+}
+
+func (f *fakeTransport) CookieJar() http.CookieJar {
+	return nil
+}
+
+func (f *fakeTransport) HttpClient() *http.Client {
+	return nil
+}
+
+func (f *fakeTransport) Send(ctx context.Context, request api.Request, response any) error {
+	switch typedResponse := response.(type) {
+	case *twofactor.QueryTimeResponse:
+		typedResponse.Response.ServerTime = time.Now().Unix()
+	case *BatchResponse:
+		values, err := request.Values()
+		if err != nil {
+			return err
+		}
+		f.values = append(f.values, values)
+		typedResponse.Success = true
+	}
+	return nil
+}
+
+func TestAcceptMany(t *testing.T) {
+	transport := &fakeTransport{}
+	twoFactorClient := &twofactor.Client{Transport: transport}
+	err := twoFactorClient.AlignTime(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	totpState, err := totp.NewState("", "aWRlbnRpdHk=")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steamID, err := steamid.ParseSteamID64("76561198006409530")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := NewClient(totpState, steamID, "", twoFactorClient, transport)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var confirmations []Confirmation
+	for i := range MaxBatchSize + 5 {
+		confirmations = append(confirmations, Confirmation{
+			ID:    strconv.Itoa(i),
+			Nonce: "nonce" + strconv.Itoa(i),
+		})
+	}
+
+	results, err := client.AcceptMany(context.Background(), confirmations)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 2 || len(results[0].Confirmations) != MaxBatchSize || len(results[1].Confirmations) != 5 {
+		t.Fatalf("expected batches of %d and 5 confirmations, got %d results", MaxBatchSize, len(results))
+	}
+
+	if len(transport.values) != 2 {
+		t.Fatalf("expected 2 multiajaxop requests, got %d", len(transport.values))
+	}
+
+	values := transport.values[1]
+	if values.Get("op") != "allow" || values.Get("tag") != "accept" {
+		t.Errorf("op=%q tag=%q, expected allow and accept", values.Get("op"), values.Get("tag"))
+	}
+
+	expectedIDs := []string{"25", "26", "27", "28", "29"}
+	if !slices.Equal(values["cid[]"], expectedIDs) {
+		t.Errorf("cid[]=%v, expected %v", values["cid[]"], expectedIDs)
+	}
+
+	expectedNonces := []string{"nonce25", "nonce26", "nonce27", "nonce28", "nonce29"}
+	if !slices.Equal(values["ck[]"], expectedNonces) {
+		t.Errorf("ck[]=%v, expected %v", values["ck[]"], expectedNonces)
+	}
+}
+
+func TestRequestMismatchedBatchNonces(t *testing.T) {
+	request := Request{
+		BatchOperation: &BatchOperation{
+			Operation: "allow",
+			IDs:       []string{"1", "2"},
+			Nonces:    []string{"nonce1"},
+		},
+	}
+
+	_, err := request.OldValues()
+	if err == nil {
+		t.Error("expected an error for a batch operation with fewer nonces than IDs")
+	}
+}
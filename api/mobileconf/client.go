@@ -5,14 +5,14 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/escrow-tf/steam/api"
+	"github.com/escrow-tf/steam/api/econ"
 	"github.com/escrow-tf/steam/api/twofactor"
 	"github.com/escrow-tf/steam/steamid"
 	"github.com/escrow-tf/steam/steamlang"
@@ -24,16 +24,64 @@ type ConfirmationType int
 
 //goland:noinspection GoUnusedConst
 const (
-	InvalidConfirmationType ConfirmationType = iota
-	TradeConfirmationType
-	MarketListingConfirmationType
-	OtherConfirmationType
+	InvalidConfirmationType           ConfirmationType = 0
+	TestConfirmationType              ConfirmationType = 1
+	TradeConfirmationType             ConfirmationType = 2
+	MarketListingConfirmationType     ConfirmationType = 3
+	FeatureOptOutConfirmationType     ConfirmationType = 4
+	PhoneNumberChangeConfirmationType ConfirmationType = 5
+	AccountRecoveryConfirmationType   ConfirmationType = 6
+	ApiKeyCreationConfirmationType    ConfirmationType = 9
+	JoinSteamFamilyConfirmationType   ConfirmationType = 11
 )
 
+func (t ConfirmationType) String() string {
+	switch t {
+	case InvalidConfirmationType:
+		return "Invalid"
+	case TestConfirmationType:
+		return "Test"
+	case TradeConfirmationType:
+		return "Trade"
+	case MarketListingConfirmationType:
+		return "MarketListing"
+	case FeatureOptOutConfirmationType:
+		return "FeatureOptOut"
+	case PhoneNumberChangeConfirmationType:
+		return "PhoneNumberChange"
+	case AccountRecoveryConfirmationType:
+		return "AccountRecovery"
+	case ApiKeyCreationConfirmationType:
+		return "ApiKeyCreation"
+	case JoinSteamFamilyConfirmationType:
+		return "JoinSteamFamily"
+	}
+
+	return fmt.Sprintf("ConfirmationType(%d)", int(t))
+}
+
+// CreatorID identifies what a confirmation is for: the trade offer id of a TradeConfirmationType, or the market
+// listing id of a MarketListingConfirmationType.
+type CreatorID uint64
+
+func (id CreatorID) String() string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+// ReauthFunc restores an expired community session, after which a confirmation request is retried once.
+type ReauthFunc func(ctx context.Context) error
+
 type Client struct {
+	// ReauthFunc is called when Steam reports that the session needs to be authenticated again. Without it,
+	// NeedsAuthError is returned.
+	ReauthFunc ReauthFunc
+	// Econ resolves the names of trade items in GetConfirmationDetails, which needs a Web API key. Without it, trade
+	// items have no Name.
+	Econ econ.Api
+
 	totpState *totp.State
 	steamID   steamid.SteamID
-	client    *http.Client
+	deviceId  string
 	twoFactor *twofactor.Client
 	transport api.Transport
 }
@@ -41,13 +89,18 @@ type Client struct {
 func NewClient(
 	totpState *totp.State,
 	steamID steamid.SteamID,
+	deviceId string,
 	twoFactorClient *twofactor.Client,
 	transport api.Transport,
 ) (*Client, error) {
+	if len(deviceId) == 0 {
+		deviceId = totp.GetDeviceId(steamID.String())
+	}
+
 	return &Client{
 		totpState: totpState,
 		steamID:   steamID,
-		client:    transport.HttpClient(),
+		deviceId:  deviceId,
 		twoFactor: twoFactorClient,
 		transport: transport,
 	}, nil
@@ -59,14 +112,24 @@ type Operation struct {
 	Nonce     string
 }
 
+// BatchOperation applies the same operation to several confirmations, as sent to multiajaxop
+type BatchOperation struct {
+	Operation string
+	IDs       []string
+	Nonces    []string
+}
+
 type Request struct {
-	Operation *Operation
-	Posts     bool
-	Path      string
-	Tag       string
+	Operation      *Operation
+	BatchOperation *BatchOperation
+	Posts          bool
+	Path           string
+	Tag            string
 
+	// the confirmation key and the values it is generated from are set by SendMobileConfRequest
 	key      []byte
 	steamID  steamid.SteamID
+	deviceId string
 	totpTime time.Time
 }
 
@@ -86,8 +149,10 @@ func (r Request) Headers() (http.Header, error) {
 	return nil, nil
 }
 
+// Retryable reports whether the request may be sent again after a failure. Accepting or declining confirmations
+// isn't idempotent, even though the operations are sent with GET.
 func (r Request) Retryable() bool {
-	return !r.Posts
+	return r.Operation == nil && r.BatchOperation == nil
 }
 
 func (r Request) RequiresApiKey() bool {
@@ -109,12 +174,12 @@ func (r Request) Url() string {
 func (r Request) OldValues() (url.Values, error) {
 	parameters := make(url.Values)
 
+	parameters.Add("p", r.deviceId)
+	parameters.Add("a", r.steamID.String())
 	parameters.Add("k", base64.StdEncoding.EncodeToString(r.key))
-	parameters.Add("p", totp.GetDeviceId(r.steamID.String()))
-	parameters.Add("c", r.steamID.String())
-	parameters.Add("t", strconv.Itoa(int(r.totpTime.Unix())))
-	parameters.Add("tag", r.Tag)
+	parameters.Add("t", strconv.FormatInt(r.totpTime.Unix(), 10))
 	parameters.Add("m", "react")
+	parameters.Add("tag", r.Tag)
 
 	if r.Operation != nil {
 		parameters.Add("op", r.Operation.Operation)
@@ -122,107 +187,132 @@ func (r Request) OldValues() (url.Values, error) {
 		parameters.Add("ck", r.Operation.Nonce)
 	}
 
+	err := addBatchOperation(parameters, r.BatchOperation)
+	if err != nil {
+		return nil, err
+	}
+
 	return parameters, nil
 }
 
+// SendMobileConfRequest signs the request with a confirmation key for the current steam time and sends it through
+// the Transport. When the response reports needsauth, the session is restored with ReauthFunc and the request is
+// sent once more.
 func (c *Client) SendMobileConfRequest(ctx context.Context, request Request, response any) error {
-	// totpTime := totp.Time(0)
-	totpTime, steamTimeErr := c.twoFactor.SteamTime()
-	if steamTimeErr != nil {
-		return steamTimeErr
+	err := c.sendMobileConfRequest(ctx, request, response)
+	if err != nil {
+		return err
 	}
 
-	key, err := c.totpState.GenerateConfirmationKey(totpTime, []byte(request.Tag))
-	if err != nil {
-		return eris.Errorf("totpState.GenerateConfirmationKey: %v", err)
+	authResponse, hasNeedsAuth := response.(needsAuthResponse)
+	if !hasNeedsAuth || !authResponse.needsAuth() {
+		return nil
 	}
 
-	parameters := make(url.Values)
-	parameters.Add("p", totp.GetDeviceId(c.steamID.String()))
-	parameters.Add("a", c.steamID.String())
-	parameters.Add("k", base64.StdEncoding.EncodeToString(key))
-	// parameters.Add("c", c.steamID.String())
-	parameters.Add("t", strconv.Itoa(int(totpTime.Unix()))) // TODO: unix or local?
-	parameters.Add("m", "react")
-	parameters.Add("tag", request.Tag)
+	if c.ReauthFunc == nil {
+		return NeedsAuthError
+	}
 
-	if request.Operation != nil {
-		parameters.Add("op", request.Operation.Operation)
-		parameters.Add("cid", request.Operation.ID)
-		parameters.Add("ck", request.Operation.Nonce)
+	err = c.ReauthFunc(ctx)
+	if err != nil {
+		return eris.Wrapf(NeedsAuthError, "reauthenticating failed: %v", err)
 	}
 
-	var httpRequest *http.Request
-	var httpRequestErr error
-	if request.Posts {
-		requestUrl := fmt.Sprintf("https://steamcommunity.com/mobileconf/%s", request.Path)
-		httpRequest, httpRequestErr = http.NewRequestWithContext(
-			ctx,
-			http.MethodPost,
-			requestUrl,
-			strings.NewReader(parameters.Encode()),
-		)
-		if httpRequestErr == nil {
-			httpRequest.Header.Add("Content-Type", api.FormContentType)
-		}
-	} else {
-		queryString := parameters.Encode()
-		requestUrl := fmt.Sprintf("https://steamcommunity.com/mobileconf/%s?%s", request.Path, queryString)
-		httpRequest, httpRequestErr = http.NewRequestWithContext(
-			ctx,
-			http.MethodGet,
-			requestUrl,
-			strings.NewReader(parameters.Encode()),
-		)
+	// the retried response must not keep needsauth from the first one
+	reflect.ValueOf(response).Elem().SetZero()
+
+	err = c.sendMobileConfRequest(ctx, request, response)
+	if err != nil {
+		return err
 	}
 
-	if httpRequestErr != nil {
-		return eris.Errorf("mobileconf request errored: %v", httpRequestErr)
+	if authResponse.needsAuth() {
+		return eris.Wrap(NeedsAuthError, "session still needed authentication after reauthenticating")
 	}
 
-	httpResponse, httpError := c.client.Do(httpRequest)
+	return nil
+}
 
-	if httpError != nil {
-		return eris.Errorf("mobileconf request errored: %v", httpError)
+func (c *Client) sendMobileConfRequest(ctx context.Context, request Request, response any) error {
+	totpTime, steamTimeErr := c.twoFactor.SteamTime()
+	if steamTimeErr != nil {
+		return steamTimeErr
 	}
 
-	if err = steamlang.EnsureSuccessResponse(httpResponse); err != nil {
-		return err
+	key, err := c.totpState.GenerateConfirmationKey(totpTime, []byte(request.Tag))
+	if err != nil {
+		return eris.Errorf("totpState.GenerateConfirmationKey: %v", err)
 	}
 
-	if err = steamlang.EnsureEResultResponse(httpResponse); err != nil {
-		return err
-	}
+	request.key = key
+	request.steamID = c.steamID
+	request.deviceId = c.deviceId
+	request.totpTime = totpTime
 
-	responseBody, err := io.ReadAll(httpResponse.Body)
+	err = c.transport.Send(ctx, request, response)
 	if err != nil {
-		return eris.Errorf("couldnt read mobileconf response body: %v", err)
+		return eris.Wrap(err, "mobileconf request errored")
 	}
 
-	err = json.Unmarshal(responseBody, response)
+	return nil
+}
+
+type Confirmation struct {
+	ID           string           `json:"id"`
+	Type         ConfirmationType `json:"type"`
+	CreatorID    CreatorID        `json:"creator_id,string"`
+	Nonce        string           `json:"nonce"`
+	TypeName     string           `json:"type_name"`
+	Headline     string           `json:"headline"`
+	Summary      []string         `json:"summary"`
+	CreationTime time.Time        `json:"-"`
+	Icon         string           `json:"icon"`
+}
+
+// confirmationJson is a Confirmation with creation_time in unix seconds, as sent by steam
+type confirmationJson struct {
+	*confirmationFields
+	CreationTime int64 `json:"creation_time"`
+}
+
+type confirmationFields Confirmation
+
+func (c *Confirmation) UnmarshalJSON(data []byte) error {
+	value := confirmationJson{
+		confirmationFields: (*confirmationFields)(c),
+	}
+
+	err := json.Unmarshal(data, &value)
 	if err != nil {
-		return eris.Errorf("couldnt unmarshal mobileconf response body: %v", err)
+		return err
 	}
 
+	c.CreationTime = time.Unix(value.CreationTime, 0)
 	return nil
 }
 
+func (c Confirmation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(confirmationJson{
+		confirmationFields: (*confirmationFields)(&c),
+		CreationTime:       c.CreationTime.Unix(),
+	})
+}
+
+// needsAuthResponse is implemented by the responses that can report needsauth
+type needsAuthResponse interface {
+	needsAuth() bool
+}
+
 type GetListResponse struct {
-	Success       bool   `json:"success"`
-	NeedsAuth     bool   `json:"needsauth,omitempty"`
-	Message       string `json:"message,omitempty"`
-	Details       string `json:"details,omitempty"`
-	Confirmations []struct {
-		ID           string           `json:"id"`
-		Type         ConfirmationType `json:"type"`
-		CreatorID    string           `json:"creator_id"`
-		Nonce        string           `json:"nonce"`
-		TypeName     string           `json:"type_name"`
-		Headline     string           `json:"headline"`
-		Summary      []string         `json:"summary"`
-		CreationTime int64            `json:"creation_time"`
-		Icon         string           `json:"icon"`
-	} `json:"conf"`
+	Success       bool           `json:"success"`
+	NeedsAuth     bool           `json:"needsauth,omitempty"`
+	Message       string         `json:"message,omitempty"`
+	Details       string         `json:"details,omitempty"`
+	Confirmations []Confirmation `json:"conf"`
+}
+
+func (r *GetListResponse) needsAuth() bool {
+	return r.NeedsAuth
 }
 
 func (c *Client) GetList(ctx context.Context) (GetListResponse, error) {
@@ -236,7 +326,7 @@ func (c *Client) GetList(ctx context.Context) (GetListResponse, error) {
 	response := GetListResponse{}
 	err := c.SendMobileConfRequest(ctx, request, &response)
 	if err != nil {
-		return GetListResponse{}, eris.Errorf("getlist mobile conf request failed: %v", err)
+		return GetListResponse{}, eris.Wrap(err, "getlist mobile conf request failed")
 	}
 
 	if !response.Success {
@@ -246,12 +336,8 @@ func (c *Client) GetList(ctx context.Context) (GetListResponse, error) {
 	return response, nil
 }
 
-type DetailsPageResponse struct {
-	TradeOffer *struct {
-		Id string `json:"id,omitempty"`
-	} `json:"tradeoffer,omitempty"`
-}
-
+// GetDetailsPage fetches the details page of a confirmation and parses what it confirms. The page is html, so
+// details steam doesn't render, such as the names of items in trade offers, are left empty.
 func (c *Client) GetDetailsPage(ctx context.Context, id string) (DetailsPageResponse, error) {
 	request := Request{
 		Posts:     false,
@@ -260,13 +346,13 @@ func (c *Client) GetDetailsPage(ctx context.Context, id string) (DetailsPageResp
 		Operation: nil,
 	}
 
-	response := DetailsPageResponse{}
-	err := c.SendMobileConfRequest(ctx, request, &response)
+	var page []byte
+	err := c.SendMobileConfRequest(ctx, request, &page)
 	if err != nil {
-		return DetailsPageResponse{}, eris.Errorf("detailspage mobile conf request failed: %v", err)
+		return DetailsPageResponse{}, eris.Wrap(err, "detailspage mobile conf request failed")
 	}
 
-	return response, nil
+	return ParseDetailsPage(page)
 }
 
 type AcceptResponse struct {
@@ -276,6 +362,10 @@ type AcceptResponse struct {
 	Details   string `json:"details,omitempty"`
 }
 
+func (r *AcceptResponse) needsAuth() bool {
+	return r.NeedsAuth
+}
+
 func (c *Client) Accept(ctx context.Context, id, nonce string) (AcceptResponse, error) {
 	request := Request{
 		Posts: false,
@@ -291,7 +381,7 @@ func (c *Client) Accept(ctx context.Context, id, nonce string) (AcceptResponse,
 	response := AcceptResponse{}
 	err := c.SendMobileConfRequest(ctx, request, &response)
 	if err != nil {
-		return AcceptResponse{}, eris.Errorf("accept mobile conf request failed: %v", err)
+		return AcceptResponse{}, eris.Wrap(err, "accept mobile conf request failed")
 	}
 
 	if !response.Success {
@@ -308,6 +398,10 @@ type DeclineResponse struct {
 	Details   string `json:"details,omitempty"`
 }
 
+func (r *DeclineResponse) needsAuth() bool {
+	return r.NeedsAuth
+}
+
 func (c *Client) Decline(ctx context.Context, id, nonce string) (DeclineResponse, error) {
 	request := Request{
 		Posts: false,
@@ -323,7 +417,29 @@ func (c *Client) Decline(ctx context.Context, id, nonce string) (DeclineResponse
 	response := DeclineResponse{}
 	err := c.SendMobileConfRequest(ctx, request, &response)
 	if err != nil {
-		return DeclineResponse{}, eris.Errorf("decline mobile conf request failed: %v", err)
+		return DeclineResponse{}, eris.Wrap(err, "decline mobile conf request failed")
 	}
 	return response, nil
 }
+
+func addBatchOperation(parameters url.Values, batchOperation *BatchOperation) error {
+	if batchOperation == nil {
+		return nil
+	}
+
+	if len(batchOperation.IDs) != len(batchOperation.Nonces) {
+		return eris.Errorf(
+			"batch operation has %d IDs but %d nonces",
+			len(batchOperation.IDs),
+			len(batchOperation.Nonces),
+		)
+	}
+
+	parameters.Add("op", batchOperation.Operation)
+	for i := range batchOperation.IDs {
+		parameters.Add("cid[]", batchOperation.IDs[i])
+		parameters.Add("ck[]", batchOperation.Nonces[i])
+	}
+
+	return nil
+}
@@ -0,0 +1,313 @@
+package mobileconf
+
+import (
+	"context"
+	"encoding/json"
+	"html"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/escrow-tf/steam/api/econ"
+	"github.com/escrow-tf/steam/steamid"
+	"github.com/rotisserie/eris"
+)
+
+const economyImageUrl = "https://community.cloudflare.steamstatic.com/economy/image/"
+
+var (
+	tradeOfferIdRegexp = regexp.MustCompile(`id="tradeofferid_(\d+)"`)
+	miniProfileRegexp  = regexp.MustCompile(`data-miniprofile="(\d+)"`)
+	avatarRegexp       = regexp.MustCompile(`(?s)class="playerAvatar[^"]*".*?<img src="([^"]+)"`)
+	tradeHeaderRegexp  = regexp.MustCompile(`(?s)<div class="tradeoffer_header">(.*?)</div>`)
+	personaRegexp      = regexp.MustCompile(`(?s)<(?:a|span)[^>]*>([^<]+)</(?:a|span)>`)
+	economyItemRegexp  = regexp.MustCompile(`(?s)data-economy-item="classinfo/(\d+)/(\d+)/?(\d*)"[^>]*>.*?<img([^>]*)>`)
+	imgSrcRegexp       = regexp.MustCompile(`src="([^"]+)"`)
+	imgAltRegexp       = regexp.MustCompile(`alt="([^"]*)"`)
+	listingHoverRegexp = regexp.MustCompile(`BuildHover\(\s*'confiteminfo',\s*(\{.*\})\s*\)`)
+	listingPriceRegexp = regexp.MustCompile(`(?s)<div class="mobileconf_listing_price">(.*?)</div>`)
+	htmlTagRegexp      = regexp.MustCompile(`<[^>]*>`)
+	whitespaceRegexp   = regexp.MustCompile(`\s+`)
+)
+
+// the sections of a trade offer holding our items and the partner's items
+const (
+	primaryItemsMarker   = `tradeoffer_items primary`
+	secondaryItemsMarker = `tradeoffer_items secondary`
+)
+
+type DetailsItem struct {
+	AppID      uint32
+	ClassID    uint64
+	InstanceID uint64
+	// Name is part of the page for market listings. For trade items, it is resolved by GetConfirmationDetails when
+	// the Client has Econ.
+	Name    string
+	IconUrl string
+}
+
+type TradeDetails struct {
+	TradeOfferID       uint64
+	PartnerSteamID     steamid.SteamID
+	PartnerPersonaName string
+	PartnerAvatarUrl   string
+	ItemsToGive        []DetailsItem
+	ItemsToReceive     []DetailsItem
+}
+
+type MarketListingDetails struct {
+	// ListingID is not part of the page, and is only set by GetConfirmationDetails
+	ListingID uint64
+	Item      DetailsItem
+	// Prices are the prices as shown on the page, such as what we receive and what the buyer pays
+	Prices []string
+}
+
+// DetailsPageResponse holds what a confirmation's details page shows. TradeOffer is set for trade confirmations, and
+// MarketListing for market listing confirmations.
+type DetailsPageResponse struct {
+	TradeOffer    *TradeDetails
+	MarketListing *MarketListingDetails
+	Html          string
+}
+
+// GetConfirmationDetails fetches the details page of the confirmation, filling in the trade offer or market listing
+// id from its creator when the page doesn't include it, and the names of trade items when the Client has Econ.
+func (c *Client) GetConfirmationDetails(ctx context.Context, confirmation Confirmation) (DetailsPageResponse, error) {
+	response, err := c.GetDetailsPage(ctx, confirmation.ID)
+	if err != nil {
+		return DetailsPageResponse{}, err
+	}
+
+	switch confirmation.Type {
+	case TradeConfirmationType:
+		if response.TradeOffer == nil {
+			response.TradeOffer = &TradeDetails{}
+		}
+		if response.TradeOffer.TradeOfferID == 0 {
+			response.TradeOffer.TradeOfferID = uint64(confirmation.CreatorID)
+		}
+
+		if c.Econ != nil {
+			err = resolveItemNames(ctx, c.Econ, response.TradeOffer.ItemsToGive, response.TradeOffer.ItemsToReceive)
+			if err != nil {
+				return DetailsPageResponse{}, err
+			}
+		}
+	case MarketListingConfirmationType:
+		if response.MarketListing == nil {
+			response.MarketListing = &MarketListingDetails{}
+		}
+		response.MarketListing.ListingID = uint64(confirmation.CreatorID)
+	}
+
+	return response, nil
+}
+
+// ParseDetailsPage parses the html of a confirmation details page.
+func ParseDetailsPage(page []byte) (DetailsPageResponse, error) {
+	pageHtml := string(page)
+	response := DetailsPageResponse{
+		Html: pageHtml,
+	}
+
+	if match := tradeOfferIdRegexp.FindStringSubmatch(pageHtml); match != nil {
+		tradeDetails, err := parseTradeDetails(pageHtml, match[1])
+		if err != nil {
+			return DetailsPageResponse{}, err
+		}
+		response.TradeOffer = tradeDetails
+	}
+
+	if match := listingHoverRegexp.FindStringSubmatch(pageHtml); match != nil {
+		listingDetails, err := parseMarketListingDetails(pageHtml, match[1])
+		if err != nil {
+			return DetailsPageResponse{}, err
+		}
+		response.MarketListing = listingDetails
+	}
+
+	return response, nil
+}
+
+func parseTradeDetails(pageHtml string, tradeOfferId string) (*TradeDetails, error) {
+	var err error
+	tradeDetails := &TradeDetails{}
+	tradeDetails.TradeOfferID, err = strconv.ParseUint(tradeOfferId, 10, 64)
+	if err != nil {
+		return nil, eris.Errorf("details page had invalid trade offer id: %v", err)
+	}
+
+	if match := miniProfileRegexp.FindStringSubmatch(pageHtml); match != nil {
+		accountId, parseErr := strconv.ParseUint(match[1], 10, 32)
+		if parseErr != nil {
+			return nil, eris.Errorf("details page had invalid partner account id: %v", parseErr)
+		}
+
+		tradeDetails.PartnerSteamID = steamid.FromIndividualAccountId(uint32(accountId))
+	}
+
+	if match := avatarRegexp.FindStringSubmatch(pageHtml); match != nil {
+		tradeDetails.PartnerAvatarUrl = match[1]
+	}
+
+	if header := tradeHeaderRegexp.FindStringSubmatch(pageHtml); header != nil {
+		if match := personaRegexp.FindStringSubmatch(header[1]); match != nil {
+			tradeDetails.PartnerPersonaName = htmlText(match[1])
+		}
+	}
+
+	// our items are listed in the primary section, followed by the partner's in the secondary section
+	primaryStart := strings.Index(pageHtml, primaryItemsMarker)
+	secondaryStart := strings.Index(pageHtml, secondaryItemsMarker)
+	if primaryStart != -1 {
+		primaryEnd := len(pageHtml)
+		if secondaryStart > primaryStart {
+			primaryEnd = secondaryStart
+		}
+		tradeDetails.ItemsToGive, err = parseTradeItems(pageHtml[primaryStart:primaryEnd])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if secondaryStart != -1 {
+		secondaryEnd := len(pageHtml)
+		if primaryStart > secondaryStart {
+			secondaryEnd = primaryStart
+		}
+		tradeDetails.ItemsToReceive, err = parseTradeItems(pageHtml[secondaryStart:secondaryEnd])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tradeDetails, nil
+}
+
+func parseTradeItems(sectionHtml string) ([]DetailsItem, error) {
+	var items []DetailsItem
+	for _, match := range economyItemRegexp.FindAllStringSubmatch(sectionHtml, -1) {
+		appId, err := strconv.ParseUint(match[1], 10, 32)
+		if err != nil {
+			return nil, eris.Errorf("details page had invalid item appid: %v", err)
+		}
+
+		classId, err := strconv.ParseUint(match[2], 10, 64)
+		if err != nil {
+			return nil, eris.Errorf("details page had invalid item classid: %v", err)
+		}
+
+		var instanceId uint64
+		if len(match[3]) != 0 {
+			instanceId, err = strconv.ParseUint(match[3], 10, 64)
+			if err != nil {
+				return nil, eris.Errorf("details page had invalid item instanceid: %v", err)
+			}
+		}
+
+		item := DetailsItem{
+			AppID:      uint32(appId),
+			ClassID:    classId,
+			InstanceID: instanceId,
+		}
+
+		if src := imgSrcRegexp.FindStringSubmatch(match[4]); src != nil {
+			item.IconUrl = html.UnescapeString(src[1])
+		}
+
+		if alt := imgAltRegexp.FindStringSubmatch(match[4]); alt != nil {
+			item.Name = htmlText(alt[1])
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+type listingHoverItem struct {
+	AppID      uint32 `json:"appid"`
+	ClassID    uint64 `json:"classid,string"`
+	InstanceID uint64 `json:"instanceid,string"`
+	Name       string `json:"name"`
+	IconUrl    string `json:"icon_url"`
+}
+
+func parseMarketListingDetails(pageHtml string, hoverJson string) (*MarketListingDetails, error) {
+	var hoverItem listingHoverItem
+	err := json.Unmarshal([]byte(hoverJson), &hoverItem)
+	if err != nil {
+		return nil, eris.Errorf("details page had invalid listing item json: %v", err)
+	}
+
+	listingDetails := &MarketListingDetails{
+		Item: DetailsItem{
+			AppID:      hoverItem.AppID,
+			ClassID:    hoverItem.ClassID,
+			InstanceID: hoverItem.InstanceID,
+			Name:       hoverItem.Name,
+		},
+	}
+
+	if len(hoverItem.IconUrl) != 0 {
+		listingDetails.Item.IconUrl = economyImageUrl + hoverItem.IconUrl
+	}
+
+	for _, match := range listingPriceRegexp.FindAllStringSubmatch(pageHtml, -1) {
+		if price := htmlText(match[1]); len(price) != 0 {
+			listingDetails.Prices = append(listingDetails.Prices, price)
+		}
+	}
+
+	return listingDetails, nil
+}
+
+// resolveItemNames fills in the names of the items from their class info, which the trade details page doesn't
+// include
+func resolveItemNames(ctx context.Context, econApi econ.Api, itemLists ...[]DetailsItem) error {
+	classesByApp := make(map[uint32][]econ.ClassInstance)
+	for _, items := range itemLists {
+		for _, item := range items {
+			class := econ.ClassInstance{ClassId: item.ClassID, InstanceId: item.InstanceID}
+			if !slices.Contains(classesByApp[item.AppID], class) {
+				classesByApp[item.AppID] = append(classesByApp[item.AppID], class)
+			}
+		}
+	}
+
+	for appId, classes := range classesByApp {
+		classInfos, err := econApi.GetAssetClassInfo(ctx, appId, classes)
+		if err != nil {
+			return eris.Wrapf(err, "GetAssetClassInfo failed for appid %d", appId)
+		}
+
+		for _, items := range itemLists {
+			for i := range items {
+				if items[i].AppID != appId {
+					continue
+				}
+
+				class := econ.ClassInstance{ClassId: items[i].ClassID, InstanceId: items[i].InstanceID}
+				classInfo, found := classInfos[class]
+				if !found {
+					continue
+				}
+
+				items[i].Name = classInfo.Name
+				if len(items[i].IconUrl) == 0 && len(classInfo.IconUrl) != 0 {
+					items[i].IconUrl = economyImageUrl + classInfo.IconUrl
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// htmlText returns the text content of an html fragment, with whitespace collapsed
+func htmlText(fragment string) string {
+	text := html.UnescapeString(htmlTagRegexp.ReplaceAllString(fragment, " "))
+	return strings.TrimSpace(whitespaceRegexp.ReplaceAllString(text, " "))
+}
@@ -0,0 +1,162 @@
+package mobileconf
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/escrow-tf/steam/api/econ"
+)
+
+//goland:noinspection SpellCheckingInspection
+const testTradeDetailsPage = `<div class="mobileconf_trade_area">
+	<div class="tradeoffer" id="tradeofferid_6000000001">
+		<div class="tradeoffer_partner">
+			<div class="playerAvatar offline" data-miniprofile="46143802">
+				<a href="https://steamcommunity.com/profiles/76561198006409530"><img src="https://avatars.steamstatic.com/avatar.jpg" alt=""></a>
+			</div>
+		</div>
+		<div class="tradeoffer_header">You offered <span class="persona">Trade &amp; Partner</span> a trade:</div>
+		<div class="tradeoffer_items_ctn">
+			<div class="tradeoffer_items primary">
+				<div class="trade_item " data-economy-item="classinfo/440/101785959/11040578">
+					<img src="https://community.cloudflare.steamstatic.com/economy/image/key/96fx96f" alt="">
+				</div>
+			</div>
+			<div class="tradeoffer_items secondary">
+				<div class="trade_item " data-economy-item="classinfo/440/2675/0">
+					<img src="https://community.cloudflare.steamstatic.com/economy/image/metal/96fx96f" alt="">
+				</div>
+				<div class="trade_item " data-economy-item="classinfo/440/2675/0">
+					<img src="https://community.cloudflare.steamstatic.com/economy/image/metal/96fx96f" alt="">
+				</div>
+			</div>
+		</div>
+	</div>
+</div>`
+
+func TestParseDetailsPage(t *testing.T) {
+	response, err := ParseDetailsPage([]byte(testTradeDetailsPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if response.TradeOffer == nil {
+		t.Fatal("expected trade offer details")
+	}
+
+	tradeOffer := response.TradeOffer
+	if tradeOffer.TradeOfferID != 6000000001 {
+		t.Errorf("TradeOfferID=%d, expected 6000000001", tradeOffer.TradeOfferID)
+	}
+
+	if tradeOffer.PartnerSteamID.String() != "76561198006409530" {
+		t.Errorf("PartnerSteamID=%s, expected 76561198006409530", tradeOffer.PartnerSteamID.String())
+	}
+
+	if tradeOffer.PartnerPersonaName != "Trade & Partner" {
+		t.Errorf("PartnerPersonaName=%q, expected %q", tradeOffer.PartnerPersonaName, "Trade & Partner")
+	}
+
+	if len(tradeOffer.ItemsToGive) != 1 || tradeOffer.ItemsToGive[0].ClassID != 101785959 {
+		t.Errorf("ItemsToGive=%+v, expected 1 item with classid 101785959", tradeOffer.ItemsToGive)
+	}
+
+	if len(tradeOffer.ItemsToReceive) != 2 || tradeOffer.ItemsToReceive[0].AppID != 440 {
+		t.Errorf("ItemsToReceive=%+v, expected 2 items with appid 440", tradeOffer.ItemsToReceive)
+	}
+
+	if response.MarketListing != nil {
+		t.Errorf("expected no market listing details for a trade confirmation")
+	}
+}
+
+//goland:noinspection SpellCheckingInspection
+const testMarketListingDetailsPage = `<div class="mobileconf_listing_item">
+	<div class="market_listing_item_img_container">
+		<img src="https://community.cloudflare.steamstatic.com/economy/image/key/96fx96f" alt="">
+	</div>
+	<div class="mobileconf_listing_prices">
+		<div class="mobileconf_listing_price">
+			You receive: <span class="price">$2.00</span>
+		</div>
+		<div class="mobileconf_listing_price">
+			Buyer pays: <span class="price">$2.30</span>
+		</div>
+	</div>
+</div>
+<script type="text/javascript">
+	BuildHover( 'confiteminfo', {"appid":440,"classid":"101785959","instanceid":"11040578","name":"Mann Co. Supply Crate Key","icon_url":"key"} );
+</script>`
+
+func TestParseMarketListingDetailsPage(t *testing.T) {
+	response, err := ParseDetailsPage([]byte(testMarketListingDetailsPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if response.MarketListing == nil {
+		t.Fatal("expected market listing details")
+	}
+
+	item := response.MarketListing.Item
+	if item.AppID != 440 || item.ClassID != 101785959 || item.InstanceID != 11040578 {
+		t.Errorf("Item=%+v, expected appid 440, classid 101785959 and instanceid 11040578", item)
+	}
+
+	if item.Name != "Mann Co. Supply Crate Key" {
+		t.Errorf("Name=%q, expected %q", item.Name, "Mann Co. Supply Crate Key")
+	}
+
+	if item.IconUrl != economyImageUrl+"key" {
+		t.Errorf("IconUrl=%q, expected %q", item.IconUrl, economyImageUrl+"key")
+	}
+
+	expectedPrices := []string{"You receive: $2.00", "Buyer pays: $2.30"}
+	if !slices.Equal(response.MarketListing.Prices, expectedPrices) {
+		t.Errorf("Prices=%q, expected %q", response.MarketListing.Prices, expectedPrices)
+	}
+
+	if response.TradeOffer != nil {
+		t.Errorf("expected no trade offer details for a market listing confirmation")
+	}
+}
+
+// fakeEcon serves class info for the items in testTradeDetailsPage
+type fakeEcon struct {
+	econ.Api
+}
+
+func (f *fakeEcon) GetAssetClassInfo(
+	ctx context.Context,
+	appId uint32,
+	classes []econ.ClassInstance,
+) (map[econ.ClassInstance]econ.AssetClassInfo, error) {
+	return map[econ.ClassInstance]econ.AssetClassInfo{
+		{ClassId: 101785959, InstanceId: 11040578}: {Name: "Mann Co. Supply Crate Key"},
+		{ClassId: 2675, InstanceId: 0}:             {Name: "Refined Metal"},
+	}, nil
+}
+
+func TestResolveItemNames(t *testing.T) {
+	response, err := ParseDetailsPage([]byte(testTradeDetailsPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tradeOffer := response.TradeOffer
+	err = resolveItemNames(context.Background(), &fakeEcon{}, tradeOffer.ItemsToGive, tradeOffer.ItemsToReceive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tradeOffer.ItemsToGive[0].Name != "Mann Co. Supply Crate Key" {
+		t.Errorf("ItemsToGive[0].Name=%q, expected %q", tradeOffer.ItemsToGive[0].Name, "Mann Co. Supply Crate Key")
+	}
+
+	for _, item := range tradeOffer.ItemsToReceive {
+		if item.Name != "Refined Metal" {
+			t.Errorf("ItemsToReceive name=%q, expected %q", item.Name, "Refined Metal")
+		}
+	}
+}
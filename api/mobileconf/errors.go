@@ -0,0 +1,8 @@
+package mobileconf
+
+import "errors"
+
+var (
+	NeedsAuthError            = errors.New("the Steam Community session has expired and needs to be authenticated again")
+	ConfirmationNotFoundError = errors.New("no mobile confirmation was found for the trade offer; it may already have been confirmed or cancelled, or did not need confirmation")
+)
@@ -0,0 +1,219 @@
+package mobileconf
+
+import (
+	"context"
+	"log"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rotisserie/eris"
+)
+
+// DefaultResponderInterval is how often a Responder polls the confirmation list when no Interval is given
+const DefaultResponderInterval = 30 * time.Second
+
+// SensitiveConfirmationTypes are the confirmation types that hand over control of the account or its api access,
+// which should never be accepted automatically.
+var SensitiveConfirmationTypes = []ConfirmationType{
+	AccountRecoveryConfirmationType,
+	PhoneNumberChangeConfirmationType,
+	ApiKeyCreationConfirmationType,
+}
+
+type Decision int
+
+//goland:noinspection GoUnusedConst
+const (
+	// AbstainDecision leaves the confirmation to the next rule
+	AbstainDecision Decision = iota
+	AcceptDecision
+	DeclineDecision
+	// IgnoreDecision leaves the confirmation pending without evaluating further rules
+	IgnoreDecision
+)
+
+// Rule decides how a Responder handles a confirmation. Rules are evaluated in order, and the first decision other
+// than AbstainDecision is acted on. Confirmations every rule abstains from are left pending, and evaluated again on
+// the next poll. Confirmations of the SensitiveConfirmationTypes are ignored instead of accepted, whatever the rules
+// decide.
+type Rule func(ctx context.Context, confirmation Confirmation) (Decision, error)
+
+// DecisionFunc is called with every decision a Responder makes. err is set when evaluating the rules or acting on
+// the decision failed, in which case the confirmation is evaluated again on the next poll.
+type DecisionFunc func(confirmation Confirmation, decision Decision, err error)
+
+// Responder polls the confirmation list and accepts or declines confirmations according to its Rules.
+type Responder struct {
+	Client Api
+	Rules  []Rule
+	// Interval is the time between polls. DefaultResponderInterval is used when zero.
+	Interval time.Duration
+	// Jitter is the most that is randomly added to each Interval. A quarter of Interval is used when zero.
+	Jitter       time.Duration
+	DecisionFunc DecisionFunc
+
+	// mutex serializes Respond, which may be called directly while Begin is polling
+	mutex sync.Mutex
+	// ignored holds the confirmations that were already decided to be ignored, so they are only reported once
+	ignored map[string]bool
+}
+
+// Begin polls and responds to confirmations in the background until ctx is cancelled.
+func (r *Responder) Begin(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(r.nextPoll()):
+			}
+
+			err := r.Respond(ctx)
+			if err != nil {
+				log.Printf("Error responding to mobile confirmations: %v", err)
+			}
+		}
+	}()
+}
+
+// Respond fetches the confirmation list once and acts on the decision for each confirmation.
+func (r *Responder) Respond(ctx context.Context) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	list, err := r.Client.GetList(ctx)
+	if err != nil {
+		return eris.Wrap(err, "GetList failed")
+	}
+
+	pending := make(map[string]bool, len(list.Confirmations))
+	for _, confirmation := range list.Confirmations {
+		pending[confirmation.ID] = true
+		if r.ignored[confirmation.ID] {
+			continue
+		}
+
+		decision, decideErr := r.decide(ctx, confirmation)
+		if decision == AcceptDecision && slices.Contains(SensitiveConfirmationTypes, confirmation.Type) {
+			decision = IgnoreDecision
+		}
+
+		if decideErr == nil {
+			decideErr = r.act(ctx, confirmation, decision)
+		}
+
+		if decideErr == nil && decision == IgnoreDecision {
+			if r.ignored == nil {
+				r.ignored = make(map[string]bool)
+			}
+			r.ignored[confirmation.ID] = true
+		}
+
+		if r.DecisionFunc != nil {
+			r.DecisionFunc(confirmation, decision, decideErr)
+		}
+	}
+
+	// forget confirmations that were handled elsewhere or expired
+	for id := range r.ignored {
+		if !pending[id] {
+			delete(r.ignored, id)
+		}
+	}
+
+	return nil
+}
+
+func (r *Responder) decide(ctx context.Context, confirmation Confirmation) (Decision, error) {
+	for _, rule := range r.Rules {
+		decision, err := rule(ctx, confirmation)
+		if err != nil {
+			return AbstainDecision, err
+		}
+
+		if decision != AbstainDecision {
+			return decision, nil
+		}
+	}
+
+	return AbstainDecision, nil
+}
+
+func (r *Responder) act(ctx context.Context, confirmation Confirmation, decision Decision) error {
+	switch decision {
+	case AcceptDecision:
+		response, err := r.Client.Accept(ctx, confirmation.ID, confirmation.Nonce)
+		if err != nil {
+			return err
+		}
+		if !response.Success {
+			return eris.Errorf("accepting confirmation %s was unsuccessful: %v", confirmation.ID, response.Message)
+		}
+	case DeclineDecision:
+		response, err := r.Client.Decline(ctx, confirmation.ID, confirmation.Nonce)
+		if err != nil {
+			return err
+		}
+		if !response.Success {
+			return eris.Errorf("declining confirmation %s was unsuccessful: %v", confirmation.ID, response.Message)
+		}
+	}
+
+	return nil
+}
+
+func (r *Responder) nextPoll() time.Duration {
+	interval := r.Interval
+	if interval <= 0 {
+		interval = DefaultResponderInterval
+	}
+
+	jitter := r.Jitter
+	if jitter <= 0 {
+		jitter = interval / 4
+	}
+
+	if jitter > 0 {
+		interval += rand.N(jitter)
+	}
+
+	return interval
+}
+
+// TypeRule makes the same decision for every confirmation of the given types, and abstains from the rest.
+func TypeRule(decision Decision, confirmationTypes ...ConfirmationType) Rule {
+	return func(ctx context.Context, confirmation Confirmation) (Decision, error) {
+		if slices.Contains(confirmationTypes, confirmation.Type) {
+			return decision, nil
+		}
+		return AbstainDecision, nil
+	}
+}
+
+// SensitiveRule makes the decision, usually DeclineDecision or IgnoreDecision, for every confirmation of one of the
+// SensitiveConfirmationTypes. An AcceptDecision is never acted on for these types, and is treated as IgnoreDecision.
+func SensitiveRule(decision Decision) Rule {
+	return TypeRule(decision, SensitiveConfirmationTypes...)
+}
+
+// OwnTradeOfferRule accepts trade confirmations whose creator, the trade offer id, is one of our own offers, such as
+// those returned by tradeoffer.Client.Create. Other trade confirmations are declined when declineOthers is set.
+func OwnTradeOfferRule(isOwnOffer func(offerID uint64) bool, declineOthers bool) Rule {
+	return func(ctx context.Context, confirmation Confirmation) (Decision, error) {
+		if confirmation.Type != TradeConfirmationType {
+			return AbstainDecision, nil
+		}
+
+		if isOwnOffer(uint64(confirmation.CreatorID)) {
+			return AcceptDecision, nil
+		}
+
+		if declineOthers {
+			return DeclineDecision, nil
+		}
+
+		return AbstainDecision, nil
+	}
+}
@@ -0,0 +1,134 @@
+package mobileconf
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+// fakeApi serves a fixed confirmation list, and records the confirmations accepted and declined. Accepting or
+// declining the confirmations in unsuccessful returns an unsuccessful response.
+type fakeApi struct {
+	Api
+	confirmations []Confirmation
+	unsuccessful  map[string]bool
+	accepted      []string
+	declined      []string
+}
+
+func (f *fakeApi) GetList(ctx context.Context) (GetListResponse, error) {
+	return GetListResponse{Success: true, Confirmations: f.confirmations}, nil
+}
+
+func (f *fakeApi) Accept(ctx context.Context, id, nonce string) (AcceptResponse, error) {
+	f.accepted = append(f.accepted, id)
+	return AcceptResponse{Success: !f.unsuccessful[id]}, nil
+}
+
+func (f *fakeApi) Decline(ctx context.Context, id, nonce string) (DeclineResponse, error) {
+	f.declined = append(f.declined, id)
+	return DeclineResponse{Success: !f.unsuccessful[id]}, nil
+}
+
+func TestResponderRespond(t *testing.T) {
+	fake := &fakeApi{
+		confirmations: []Confirmation{
+			{ID: "1", Type: TradeConfirmationType, CreatorID: 100},
+			{ID: "2", Type: TradeConfirmationType, CreatorID: 200},
+			{ID: "3", Type: PhoneNumberChangeConfirmationType},
+			{ID: "4", Type: MarketListingConfirmationType},
+			{ID: "5", Type: ApiKeyCreationConfirmationType},
+		},
+	}
+
+	decisions := make(map[string][]Decision)
+	responder := &Responder{
+		Client: fake,
+		Rules: []Rule{
+			OwnTradeOfferRule(func(offerID uint64) bool { return offerID == 100 }, false),
+			TypeRule(DeclineDecision, PhoneNumberChangeConfirmationType),
+			TypeRule(IgnoreDecision, MarketListingConfirmationType),
+			// sensitive confirmations are never accepted, even by a rule that would
+			TypeRule(AcceptDecision, ApiKeyCreationConfirmationType),
+		},
+		DecisionFunc: func(confirmation Confirmation, decision Decision, err error) {
+			if err != nil {
+				t.Errorf("confirmation %s: %v", confirmation.ID, err)
+			}
+			decisions[confirmation.ID] = append(decisions[confirmation.ID], decision)
+		},
+	}
+
+	for range 2 {
+		err := responder.Respond(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := map[string][]Decision{
+		"1": {AcceptDecision, AcceptDecision},
+		"2": {AbstainDecision, AbstainDecision},
+		"3": {DeclineDecision, DeclineDecision},
+		"4": {IgnoreDecision},
+		"5": {IgnoreDecision},
+	}
+	for id, expectedDecisions := range expected {
+		if !slices.Equal(decisions[id], expectedDecisions) {
+			t.Errorf("confirmation %s: decisions=%v, expected %v", id, decisions[id], expectedDecisions)
+		}
+	}
+
+	if len(fake.accepted) != 2 || fake.accepted[0] != "1" {
+		t.Errorf("accepted=%v, expected confirmation 1 twice", fake.accepted)
+	}
+
+	if len(fake.declined) != 2 || fake.declined[0] != "3" {
+		t.Errorf("declined=%v, expected confirmation 3 twice", fake.declined)
+	}
+
+	// ignored confirmations are forgotten once they leave the list
+	fake.confirmations = fake.confirmations[:1]
+	err := responder.Respond(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(responder.ignored) != 0 {
+		t.Errorf("ignored=%v, expected it to be empty", responder.ignored)
+	}
+}
+
+func TestResponderRespondUnsuccessful(t *testing.T) {
+	fake := &fakeApi{
+		confirmations: []Confirmation{
+			{ID: "1", Type: TradeConfirmationType, CreatorID: 100},
+		},
+		unsuccessful: map[string]bool{"1": true},
+	}
+
+	var errs []error
+	responder := &Responder{
+		Client: fake,
+		Rules:  []Rule{OwnTradeOfferRule(func(offerID uint64) bool { return false }, true)},
+		DecisionFunc: func(confirmation Confirmation, decision Decision, err error) {
+			errs = append(errs, err)
+		},
+	}
+
+	err := responder.Respond(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(errs) != 1 || errs[0] == nil {
+		t.Errorf("errs=%v, expected an error for the unsuccessful decline", errs)
+	}
+}
+
+func TestResponderNextPoll(t *testing.T) {
+	responder := &Responder{Interval: 1}
+	if poll := responder.nextPoll(); poll != 1 {
+		t.Errorf("nextPoll()=%v, expected 1ns", poll)
+	}
+}
@@ -0,0 +1,75 @@
+package mobileconf
+
+import (
+	"context"
+	"time"
+
+	"github.com/rotisserie/eris"
+)
+
+const (
+	// TradeOfferLookupTimeout is how long ConfirmTradeOffer and CancelTradeOffer wait for a new trade offer's
+	// confirmation to appear in the confirmation list
+	TradeOfferLookupTimeout = 30 * time.Second
+	// tradeOfferLookupInterval is the time between fetches of the confirmation list while waiting
+	tradeOfferLookupInterval = 2 * time.Second
+)
+
+// ConfirmTradeOffer accepts the confirmation for a trade offer, such as one that tradeoffer.Client.Create reported
+// as needing mobile confirmation. Confirmations can take a few seconds to appear, so the confirmation list is
+// fetched repeatedly for up to TradeOfferLookupTimeout. ConfirmationNotFoundError is returned if it never appears.
+func (c *Client) ConfirmTradeOffer(ctx context.Context, offerID uint64) error {
+	confirmation, err := c.findTradeOfferConfirmation(ctx, offerID)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Accept(ctx, confirmation.ID, confirmation.Nonce)
+	return err
+}
+
+// CancelTradeOffer declines the confirmation for a trade offer, which cancels the offer. Like ConfirmTradeOffer, it
+// waits for the confirmation to appear and returns ConfirmationNotFoundError if it never does.
+func (c *Client) CancelTradeOffer(ctx context.Context, offerID uint64) error {
+	confirmation, err := c.findTradeOfferConfirmation(ctx, offerID)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.Decline(ctx, confirmation.ID, confirmation.Nonce)
+	if err != nil {
+		return err
+	}
+
+	if !response.Success {
+		return eris.Errorf("decline mobile conf request failed: %v", response.Message)
+	}
+
+	return nil
+}
+
+func (c *Client) findTradeOfferConfirmation(ctx context.Context, offerID uint64) (Confirmation, error) {
+	deadline := time.Now().Add(TradeOfferLookupTimeout)
+	for {
+		list, err := c.GetList(ctx)
+		if err != nil {
+			return Confirmation{}, err
+		}
+
+		for _, confirmation := range list.Confirmations {
+			if confirmation.Type == TradeConfirmationType && uint64(confirmation.CreatorID) == offerID {
+				return confirmation, nil
+			}
+		}
+
+		if time.Now().Add(tradeOfferLookupInterval).After(deadline) {
+			return Confirmation{}, eris.Wrapf(ConfirmationNotFoundError, "trade offer %d", offerID)
+		}
+
+		select {
+		case <-ctx.Done():
+			return Confirmation{}, eris.Errorf("waiting for trade offer %d confirmation: %v", offerID, ctx.Err())
+		case <-time.After(tradeOfferLookupInterval):
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package mobileconf
+
+import (
+	"context"
+	"time"
+
+	"github.com/rotisserie/eris"
+)
+
+const (
+	// DefaultWatchInterval is how often Watch polls the confirmation list when no interval is given
+	DefaultWatchInterval = 30 * time.Second
+	// maxWatchBackoff is the longest Watch waits between polls while GetList is failing
+	maxWatchBackoff = 5 * time.Minute
+	// maxWatchFailures is how many consecutive GetList failures end a Watch
+	maxWatchFailures = 10
+	// watchBufferSize leaves room for the final event when the receiver is slow
+	watchBufferSize = 16
+)
+
+type ConfirmationEventType int
+
+//goland:noinspection GoUnusedConst
+const (
+	InvalidConfirmationEvent ConfirmationEventType = iota
+	AddedConfirmationEvent
+	RemovedConfirmationEvent
+	// ClosedConfirmationEvent is the last event sent before the channel is closed, and holds the reason in Err
+	ClosedConfirmationEvent
+)
+
+type ConfirmationEvent struct {
+	Type         ConfirmationEventType
+	Confirmation Confirmation
+	Err          error
+}
+
+// Watch polls the confirmation list every interval until ctx is cancelled, sending an AddedConfirmationEvent for
+// every confirmation that appears, including those pending when Watch is called, and a RemovedConfirmationEvent for
+// every one that disappears. Failed polls are retried with exponential backoff. The channel is closed after a
+// ClosedConfirmationEvent holding the error that ended the watch. After repeated failures, that event waits for the
+// receiver; once ctx is cancelled, it is only sent if the channel has room.
+func (c *Client) Watch(ctx context.Context, interval time.Duration) <-chan ConfirmationEvent {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	events := make(chan ConfirmationEvent, watchBufferSize)
+	go func() {
+		defer close(events)
+
+		err := c.watch(ctx, interval, events)
+		closed := ConfirmationEvent{Type: ClosedConfirmationEvent, Err: err}
+
+		if ctx.Err() != nil {
+			// the receiver may have stopped reading after cancelling ctx, so don't wait for room
+			select {
+			case events <- closed:
+			default:
+			}
+			return
+		}
+
+		events <- closed
+	}()
+
+	return events
+}
+
+func (c *Client) watch(ctx context.Context, interval time.Duration, events chan<- ConfirmationEvent) error {
+	var known []Confirmation
+	failures := 0
+	wait := time.Duration(0)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+
+		list, err := c.GetList(ctx)
+		if err != nil {
+			failures++
+			if failures >= maxWatchFailures {
+				return eris.Wrapf(err, "GetList failed %d times in a row", failures)
+			}
+
+			wait = min(interval<<failures, maxWatchBackoff)
+			continue
+		}
+
+		failures = 0
+		wait = interval
+
+		for _, event := range diffConfirmations(known, list.Confirmations) {
+			sendErr := sendEvent(ctx, events, event)
+			if sendErr != nil {
+				return sendErr
+			}
+		}
+
+		known = list.Confirmations
+	}
+}
+
+// diffConfirmations returns an AddedConfirmationEvent for every confirmation in pending that isn't in known, followed
+// by a RemovedConfirmationEvent for every confirmation in known that isn't in pending
+func diffConfirmations(known []Confirmation, pending []Confirmation) []ConfirmationEvent {
+	knownIds := make(map[string]bool, len(known))
+	for _, confirmation := range known {
+		knownIds[confirmation.ID] = true
+	}
+
+	pendingIds := make(map[string]bool, len(pending))
+	for _, confirmation := range pending {
+		pendingIds[confirmation.ID] = true
+	}
+
+	var events []ConfirmationEvent
+	for _, confirmation := range pending {
+		if !knownIds[confirmation.ID] {
+			events = append(events, ConfirmationEvent{Type: AddedConfirmationEvent, Confirmation: confirmation})
+		}
+	}
+
+	for _, confirmation := range known {
+		if !pendingIds[confirmation.ID] {
+			events = append(events, ConfirmationEvent{Type: RemovedConfirmationEvent, Confirmation: confirmation})
+		}
+	}
+
+	return events
+}
+
+func sendEvent(ctx context.Context, events chan<- ConfirmationEvent, event ConfirmationEvent) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case events <- event:
+		return nil
+	}
+}
@@ -0,0 +1,35 @@
+package mobileconf
+
+import "testing"
+
+func TestDiffConfirmations(t *testing.T) {
+	known := []Confirmation{{ID: "1"}, {ID: "2"}, {ID: "3"}}
+	pending := []Confirmation{{ID: "2"}, {ID: "4"}, {ID: "5"}}
+
+	events := diffConfirmations(known, pending)
+
+	expected := []struct {
+		eventType ConfirmationEventType
+		id        string
+	}{
+		{AddedConfirmationEvent, "4"},
+		{AddedConfirmationEvent, "5"},
+		{RemovedConfirmationEvent, "1"},
+		{RemovedConfirmationEvent, "3"},
+	}
+
+	if len(events) != len(expected) {
+		t.Fatalf("events=%+v, expected %d events", events, len(expected))
+	}
+
+	for i, event := range events {
+		if event.Type != expected[i].eventType || event.Confirmation.ID != expected[i].id {
+			t.Errorf("events[%d]=%v %s, expected %v %s",
+				i, event.Type, event.Confirmation.ID, expected[i].eventType, expected[i].id)
+		}
+	}
+
+	if events := diffConfirmations(pending, pending); len(events) != 0 {
+		t.Errorf("events=%+v, expected none for an unchanged list", events)
+	}
+}
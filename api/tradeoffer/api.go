@@ -17,6 +17,12 @@ type Api interface {
 		myItems, theirItems []Item,
 		message string,
 	) (CreateResponse, error)
+	Counter(
+		ctx context.Context,
+		originalOfferId uint64,
+		myItems, theirItems []Item,
+		message string,
+	) (CreateResponse, error)
 
 	GetPartnerInventory(
 		ctx context.Context,
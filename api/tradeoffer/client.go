@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/escrow-tf/steam/api"
+	"github.com/escrow-tf/steam/api/econ"
 	"github.com/escrow-tf/steam/steamid"
 	"github.com/escrow-tf/steam/steamlang"
 	"github.com/rotisserie/eris"
@@ -21,6 +22,8 @@ type SessionIdFunc func(transport api.Transport) (string, error)
 type Client struct {
 	Transport     api.Transport
 	SessionIdFunc SessionIdFunc
+	// Econ looks up the partner of an offer being countered. A client using Transport is used when nil.
+	Econ econ.Api
 }
 
 type ActionResponse struct {
@@ -139,6 +142,8 @@ type CreateRequest struct {
 	CreateParamsJson string
 	PartnerAccountId uint32
 	PartnerToken     string
+	// CounteredOfferId is the offer this offer counters, or zero for a new offer
+	CounteredOfferId uint64
 }
 
 func (c CreateRequest) Values() (url.Values, error) {
@@ -181,10 +186,19 @@ func (c CreateRequest) OldValues() (url.Values, error) {
 	values.Add("tradeoffermessage", c.Message)
 	values.Add("json_tradeoffer", c.OfferJson)
 	values.Add("trade_offer_create_params", c.CreateParamsJson)
+	if c.CounteredOfferId != 0 {
+		values.Add("tradeofferid_countered", strconv.FormatUint(c.CounteredOfferId, 10))
+	}
 	return values, nil
 }
 
 func (c CreateRequest) Headers() (http.Header, error) {
+	if c.CounteredOfferId != 0 {
+		return http.Header{
+			"Referer": []string{fmt.Sprintf("https://steamcommunity.com/tradeoffer/%d/", c.CounteredOfferId)},
+		}, nil
+	}
+
 	encodedPartnerAccountId := strconv.FormatUint(uint64(c.PartnerAccountId), 10)
 	encodedPartnerToken := url.QueryEscape(c.PartnerToken)
 	referer := fmt.Sprintf(
@@ -208,6 +222,44 @@ func (c *Client) Create(
 	partnerToken string,
 	myItems, theirItems []Item,
 	message string,
+) (CreateResponse, error) {
+	return c.send(ctx, other, partnerToken, 0, myItems, theirItems, message)
+}
+
+// Counter sends a counter-offer to the partner of the offer originalOfferId, replacing it with an offer of myItems
+// for theirItems. The partner is looked up with econ.Api.GetTradeOffer, which requires a Web API key; without one,
+// Counter fails with api.MissingApiKeyError.
+func (c *Client) Counter(
+	ctx context.Context,
+	originalOfferId uint64,
+	myItems, theirItems []Item,
+	message string,
+) (CreateResponse, error) {
+	econClient := c.Econ
+	if econClient == nil {
+		econClient = &econ.Client{Transport: c.Transport}
+	}
+
+	original, err := econClient.GetTradeOffer(ctx, originalOfferId)
+	if err != nil {
+		return CreateResponse{}, eris.Wrapf(err, "error retrieving offer %d to counter", originalOfferId)
+	}
+
+	if original.Offer == nil {
+		return CreateResponse{}, eris.Errorf("error retrieving offer %d to counter: no offer returned", originalOfferId)
+	}
+
+	other := steamid.FromIndividualAccountId(original.Offer.OtherAccountId)
+	return c.send(ctx, other, "", originalOfferId, myItems, theirItems, message)
+}
+
+func (c *Client) send(
+	ctx context.Context,
+	other steamid.SteamID,
+	partnerToken string,
+	counteredOfferId uint64,
+	myItems, theirItems []Item,
+	message string,
 ) (CreateResponse, error) {
 	sessionId, sessionIdErr := c.SessionIdFunc(c.Transport)
 	if sessionIdErr != nil {
@@ -252,6 +304,7 @@ func (c *Client) Create(
 		CreateParamsJson: string(createParamsJson),
 		PartnerAccountId: other.AccountId(),
 		PartnerToken:     partnerToken,
+		CounteredOfferId: counteredOfferId,
 	}
 	var response CreateResponse
 	sendErr := c.Transport.Send(ctx, request, &response)
@@ -259,6 +312,20 @@ func (c *Client) Create(
 		return CreateResponse{}, eris.Errorf("error creating new Offer: %v", sendErr)
 	}
 
+	err := createResponseError(response)
+	if err != nil {
+		return CreateResponse{}, err
+	}
+
+	if response.TradeOfferId == 0 {
+		return CreateResponse{}, eris.Errorf("error creating offer: steam returned tradeofferid 0")
+	}
+
+	return response, nil
+}
+
+// createResponseError maps the strError of a CreateResponse to an error, or nil if there was none
+func createResponseError(response CreateResponse) error {
 	// There are a couple of error formats we're likely to receive back:
 	// A generic error message with an error number at the end:
 	//  {"strError":"There was an error sending your trade offer.  Please try again later. (ERROR NUMBER)"}
@@ -271,50 +338,50 @@ func (c *Client) Create(
 	// give us an EResult header in the response.
 
 	if strings.HasPrefix(response.Error, "There was an error sending your trade offer.  Please try again later. (") {
-		leftParenIdx := strings.Index(response.Error, "(")
-		rightParenIdx := strings.Index(response.Error, ")")
-		eResultString := response.Error[leftParenIdx:rightParenIdx]
+		leftParenIdx := strings.LastIndex(response.Error, "(")
+		rightParenIdx := strings.LastIndex(response.Error, ")")
+		if rightParenIdx < leftParenIdx {
+			return eris.Errorf("error sending offer: %v", response.Error)
+		}
+
+		eResultString := response.Error[leftParenIdx+1 : rightParenIdx]
 		eResult, err := strconv.ParseInt(eResultString, 10, 32)
 		if err != nil {
-			return CreateResponse{}, eris.Errorf("error sending offer: %v", response.Error)
+			return eris.Errorf("error sending offer: %v", response.Error)
 		}
 
 		switch steamlang.EResult(eResult) {
 		case steamlang.InvalidStateResult:
-			return CreateResponse{}, InvalidStateError
+			return InvalidStateError
 		case steamlang.AccessDeniedResult:
-			return CreateResponse{}, AccessDeniedError
+			return AccessDeniedError
 		case steamlang.TimeoutResult:
-			return CreateResponse{}, TimeoutError
+			return TimeoutError
 		case steamlang.ServiceUnavailableResult:
-			return CreateResponse{}, ServiceUnavailableError
+			return ServiceUnavailableError
 		case steamlang.LimitExceededResult:
-			return CreateResponse{}, TooManyTradeOffersError
+			return TooManyTradeOffersError
 		case steamlang.RevokedResult:
-			return CreateResponse{}, ItemsDontExistError
+			return ItemsDontExistError
 		case steamlang.AlreadyRedeemedResult:
-			return CreateResponse{}, ChangedPersonaNameRecentlyError
+			return ChangedPersonaNameRecentlyError
 		}
 
-		return CreateResponse{}, steamlang.EResultError(steamlang.EResult(eResult))
+		return steamlang.EResultError(steamlang.EResult(eResult))
 	}
 
 	if strings.HasPrefix(
 		response.Error,
 		"You have sent too many trade offers, or have too many outstanding trade offers with",
 	) {
-		return CreateResponse{}, TooManyTradeOffersError
+		return TooManyTradeOffersError
 	}
 
 	if response.Error != "" {
-		return CreateResponse{}, eris.Errorf("error sending offer: %v", response.Error)
+		return eris.Errorf("error sending offer: %v", response.Error)
 	}
 
-	if response.TradeOfferId == 0 {
-		return CreateResponse{}, eris.Errorf("error creating offer: steam returned tradeofferid 0")
-	}
-
-	return response, nil
+	return nil
 }
 
 type PartnerInventoryRequest struct {
@@ -0,0 +1,22 @@
+package tradeoffer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCreateResponseError(t *testing.T) {
+	response := CreateResponse{
+		Error: "There was an error sending your trade offer.  Please try again later. (26)",
+	}
+
+	err := createResponseError(response)
+	if !errors.Is(err, ItemsDontExistError) {
+		t.Errorf("err=%v, expected ItemsDontExistError", err)
+	}
+
+	err = createResponseError(CreateResponse{TradeOfferId: 1})
+	if err != nil {
+		t.Errorf("err=%v, expected nil", err)
+	}
+}
@@ -0,0 +1,217 @@
+package tradeoffer
+
+import (
+	"context"
+	"log"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/escrow-tf/steam/api/econ"
+	"github.com/rotisserie/eris"
+)
+
+const (
+	// DefaultManagerInterval is how often a Manager polls trade offers when no Interval is given
+	DefaultManagerInterval = 30 * time.Second
+	// cutoffMargin is taken off the historical cutoff sent to steam, so offers updated while a poll was in flight,
+	// or by a server with a slightly different clock, aren't missed
+	cutoffMargin = 5 * time.Minute
+)
+
+type EventType int
+
+//goland:noinspection GoUnusedConst
+const (
+	InvalidEvent EventType = iota
+	// NewOfferEvent is sent for every active offer we receive, including those pending when polling starts
+	NewOfferEvent
+	// SentOfferChangedEvent is sent when an offer we sent changes state
+	SentOfferChangedEvent
+	// ReceivedOfferChangedEvent is sent when an offer we received changes state
+	ReceivedOfferChangedEvent
+	// EscrowEvent is sent when an offer is placed on hold, after the event for its change of state
+	EscrowEvent
+	// InvalidItemsEvent is sent when items in an offer are no longer available, after the event for its change of
+	// state
+	InvalidItemsEvent
+)
+
+type Event struct {
+	Type  EventType
+	Offer *econ.TradeOffer
+	// PreviousState is the state of the offer at the previous poll, or zero if the offer wasn't known
+	PreviousState econ.OfferState
+}
+
+// EventFunc is called with every event a Manager finds, in the order they were found.
+type EventFunc func(event Event)
+
+// PollStateFunc is called with the Manager's PollState after every successful poll, so it can be persisted.
+type PollStateFunc func(state PollState)
+
+// PollState is what a Manager remembers between polls. It can be marshalled to JSON and passed to NewManager after a
+// restart, so events that were already sent aren't sent again.
+type PollState struct {
+	// HistoricalCutoff is the unix time of the latest offer update seen, sent to steam as time_historical_cutoff
+	HistoricalCutoff uint32                     `json:"historical_cutoff"`
+	OfferStates      map[uint64]econ.OfferState `json:"offer_states"`
+}
+
+// Manager polls the offers we sent and received, and sends an Event whenever one of them changes.
+type Manager struct {
+	Econ econ.Api
+	// Interval is the time between polls. DefaultManagerInterval is used when zero.
+	Interval      time.Duration
+	EventFunc     EventFunc
+	PollStateFunc PollStateFunc
+
+	// pollMutex serializes polls, while mutex guards state so State can be called from the callbacks
+	pollMutex sync.Mutex
+	mutex     sync.RWMutex
+	state     PollState
+}
+
+// NewManager creates a Manager that resumes from state, which may be the zero value when there is no saved state.
+func NewManager(econApi econ.Api, state PollState) *Manager {
+	state.OfferStates = maps.Clone(state.OfferStates)
+	if state.OfferStates == nil {
+		state.OfferStates = make(map[uint64]econ.OfferState)
+	}
+
+	return &Manager{
+		Econ:  econApi,
+		state: state,
+	}
+}
+
+// State returns a copy of the Manager's PollState.
+func (m *Manager) State() PollState {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return PollState{
+		HistoricalCutoff: m.state.HistoricalCutoff,
+		OfferStates:      maps.Clone(m.state.OfferStates),
+	}
+}
+
+// Begin polls trade offers in the background until ctx is cancelled.
+func (m *Manager) Begin(ctx context.Context) {
+	go func() {
+		for {
+			err := m.Poll(ctx)
+			if err != nil {
+				log.Printf("Error polling trade offers: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(m.interval()):
+			}
+		}
+	}()
+}
+
+// Poll fetches the active offers and those updated since the last poll, sending events for those that changed.
+func (m *Manager) Poll(ctx context.Context) error {
+	m.pollMutex.Lock()
+	defer m.pollMutex.Unlock()
+
+	state := m.State()
+	if state.HistoricalCutoff == 0 {
+		// nothing has been polled before, so only active offers are of interest
+		state.HistoricalCutoff = uint32(time.Now().Unix())
+	}
+
+	cutoff := state.HistoricalCutoff - min(state.HistoricalCutoff, uint32(cutoffMargin.Seconds()))
+	response, err := m.Econ.GetTradeOffers(ctx, true, true, false, true, false, cutoff)
+	if err != nil {
+		return eris.Wrap(err, "GetTradeOffers failed")
+	}
+
+	var events []Event
+	seen := make(map[uint64]bool, len(response.Sent)+len(response.Received))
+	observe := func(offer *econ.TradeOffer, sent bool) {
+		events = append(events, offerEvents(offer, sent, state.OfferStates)...)
+		seen[offer.TradeOfferId] = true
+		state.OfferStates[offer.TradeOfferId] = offer.State
+		state.HistoricalCutoff = max(state.HistoricalCutoff, offer.TimeUpdated)
+	}
+	for _, offer := range response.Sent {
+		observe(offer, true)
+	}
+	for _, offer := range response.Received {
+		observe(offer, false)
+	}
+
+	// offers that reached a final state before the cutoff won't be returned again unless they change
+	for id, offerState := range state.OfferStates {
+		if !seen[id] && isFinalOfferState(offerState) {
+			delete(state.OfferStates, id)
+		}
+	}
+
+	m.mutex.Lock()
+	m.state = state
+	m.mutex.Unlock()
+
+	if m.EventFunc != nil {
+		for _, event := range events {
+			m.EventFunc(event)
+		}
+	}
+
+	if m.PollStateFunc != nil {
+		m.PollStateFunc(m.State())
+	}
+
+	return nil
+}
+
+func (m *Manager) interval() time.Duration {
+	if m.Interval <= 0 {
+		return DefaultManagerInterval
+	}
+	return m.Interval
+}
+
+// offerEvents returns the events for an offer given the states it was in at the previous poll
+func offerEvents(offer *econ.TradeOffer, sent bool, offerStates map[uint64]econ.OfferState) []Event {
+	previous, known := offerStates[offer.TradeOfferId]
+	if known && previous == offer.State {
+		return nil
+	}
+
+	var events []Event
+	switch {
+	case !known && !sent && offer.State == econ.ActiveOfferState:
+		events = append(events, Event{Type: NewOfferEvent, Offer: offer})
+	case !known && sent && (offer.State == econ.ActiveOfferState ||
+		offer.State == econ.CreatedNeedsConfirmationOfferState):
+		// this is how offers we send start out, so there is no change to report
+	case sent:
+		events = append(events, Event{Type: SentOfferChangedEvent, Offer: offer, PreviousState: previous})
+	default:
+		events = append(events, Event{Type: ReceivedOfferChangedEvent, Offer: offer, PreviousState: previous})
+	}
+
+	switch offer.State {
+	case econ.InEscrowOfferState:
+		events = append(events, Event{Type: EscrowEvent, Offer: offer, PreviousState: previous})
+	case econ.InvalidItemsOfferState:
+		events = append(events, Event{Type: InvalidItemsEvent, Offer: offer, PreviousState: previous})
+	}
+
+	return events
+}
+
+// isFinalOfferState reports whether an offer in this state can no longer change
+func isFinalOfferState(offerState econ.OfferState) bool {
+	switch offerState {
+	case econ.ActiveOfferState, econ.CreatedNeedsConfirmationOfferState, econ.InEscrowOfferState:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package tradeoffer
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/escrow-tf/steam/api/econ"
+)
+
+type fakeEcon struct {
+	response econ.GetTradeOffersResponse
+}
+
+func (f *fakeEcon) GetTradeOffer(ctx context.Context, id uint64) (*econ.GetTradeOfferResponse, error) {
+	return nil, nil
+}
+
+func (f *fakeEcon) GetAssetClassInfo(
+	ctx context.Context,
+	appId uint32,
+	classes []econ.ClassInstance,
+) (map[econ.ClassInstance]econ.AssetClassInfo, error) {
+	return nil, nil
+}
+
+func (f *fakeEcon) GetTradeOffers(
+	ctx context.Context,
+	getSent, getReceived, getDescriptions, activeOnly, historicalOnly bool,
+	historicalCutoff uint32,
+) (*econ.GetTradeOffersResponse, error) {
+	return &f.response, nil
+}
+
+func TestManagerPoll(t *testing.T) {
+	fake := &fakeEcon{
+		response: econ.GetTradeOffersResponse{
+			Sent: []*econ.TradeOffer{
+				{TradeOfferId: 1, State: econ.ActiveOfferState, TimeUpdated: 1700000000},
+			},
+			Received: []*econ.TradeOffer{
+				{TradeOfferId: 2, State: econ.ActiveOfferState, TimeUpdated: 1700000000},
+			},
+		},
+	}
+
+	var events []EventType
+	manager := NewManager(fake, PollState{})
+	manager.EventFunc = func(event Event) {
+		events = append(events, event.Type)
+	}
+
+	poll := func(expected ...EventType) {
+		t.Helper()
+		events = nil
+		err := manager.Poll(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(events, expected) {
+			t.Errorf("events=%v, expected %v", events, expected)
+		}
+	}
+
+	poll(NewOfferEvent)
+	poll()
+
+	fake.response.Sent[0].State = econ.InEscrowOfferState
+	poll(SentOfferChangedEvent, EscrowEvent)
+
+	// a manager resuming from the saved state shouldn't send the events again
+	stateJson, err := json.Marshal(manager.State())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var state PollState
+	err = json.Unmarshal(stateJson, &state)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager = NewManager(fake, state)
+	manager.EventFunc = func(event Event) {
+		events = append(events, event.Type)
+	}
+
+	poll()
+
+	fake.response.Received[0].State = econ.InvalidItemsOfferState
+	poll(ReceivedOfferChangedEvent, InvalidItemsEvent)
+}
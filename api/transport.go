@@ -8,7 +8,6 @@ import (
 	"log"
 	"mime/multipart"
 	"net/http"
-	"net/http/cookiejar"
 	"net/http/httputil"
 	"net/url"
 	"strings"
@@ -112,7 +111,7 @@ type HttpTransportOptions struct {
 }
 
 func NewTransport(options HttpTransportOptions) *HttpTransport {
-	jar, err := cookiejar.New(nil)
+	jar, err := newRecordingJar()
 	if err != nil {
 		panic("Failed to create cookie jar, which should never happen as cookiejar.New does not return any errors")
 	}
@@ -155,6 +154,12 @@ func (c HttpTransport) CookieJar() http.CookieJar {
 	return c.client.Jar
 }
 
+// Cookies returns the cookies the transport sends to u, with the path, domain, expiry and other attributes they
+// were set with.
+func (c HttpTransport) Cookies(u *url.URL) []*http.Cookie {
+	return c.client.Jar.(*recordingJar).fullCookies(u)
+}
+
 // Send sends a specialized HTTP Request to steam.
 func (c HttpTransport) Send(ctx context.Context, request Request, response any) error {
 	//rv := reflect.ValueOf(response)
@@ -181,11 +186,12 @@ func (c HttpTransport) Send(ctx context.Context, request Request, response any)
 	}
 
 	requestUrl := request.Url()
-	if !strings.HasSuffix(requestUrl, "?") {
-		requestUrl += "?"
-	}
 
 	if request.RequiresApiKey() {
+		if len(c.webApiKey) == 0 {
+			return MissingApiKeyError
+		}
+
 		if queryValues == nil {
 			queryValues = make(url.Values)
 		}
@@ -193,7 +199,13 @@ func (c HttpTransport) Send(ctx context.Context, request Request, response any)
 		queryValues.Add("key", c.webApiKey)
 	}
 
-	if queryValues != nil {
+	if len(queryValues) != 0 {
+		// some requests carry part of their query in the url, e.g. access_token or origin
+		if strings.Contains(requestUrl, "?") {
+			requestUrl += "&"
+		} else {
+			requestUrl += "?"
+		}
 		requestUrl += queryValues.Encode()
 	}
 
@@ -294,7 +306,10 @@ func (c HttpTransport) Send(ctx context.Context, request Request, response any)
 			return eris.Errorf("couldn't read request: %v", err)
 		}
 
-		if strings.Contains(httpResponse.Header.Get("Content-Type"), JsonContentType) {
+		if rawResponse, isRaw := response.(*[]byte); isRaw {
+			// pages that aren't json or protobuf, such as html, are returned to the caller as-is
+			*rawResponse = responseBody
+		} else if strings.Contains(httpResponse.Header.Get("Content-Type"), JsonContentType) {
 			err = json.Unmarshal(responseBody, response)
 			if err != nil {
 				return eris.Errorf("couldnt unmarshal response: %v", err)
@@ -3,10 +3,40 @@ package twofactor
 import (
 	"context"
 	"time"
+
+	"github.com/escrow-tf/steam/steamid"
 )
 
 type Api interface {
 	SteamTime() (time.Time, error)
+	TimeDrift() (time.Duration, error)
 	AlignTime(ctx context.Context) error
+	BeginAligning(ctx context.Context, interval time.Duration)
 	QueryTime(ctx context.Context) (*QueryTimeResponse, error)
+	AddAuthenticator(
+		ctx context.Context,
+		accessToken string,
+		steamID steamid.SteamID,
+		deviceId string,
+	) (*AddAuthenticatorResponse, error)
+	FinalizeAddAuthenticator(
+		ctx context.Context,
+		accessToken string,
+		steamID steamid.SteamID,
+		authenticatorCode string,
+		authenticatorTime time.Time,
+		activationCode string,
+	) (*FinalizeAddAuthenticatorResponse, error)
+	RemoveAuthenticator(
+		ctx context.Context,
+		accessToken string,
+		revocationCode string,
+		scheme SteamGuardScheme,
+	) (*RemoveAuthenticatorResponse, error)
+	QueryStatus(
+		ctx context.Context,
+		accessToken string,
+		steamID steamid.SteamID,
+		deviceId string,
+	) (*AuthenticatorStatus, error)
 }
@@ -0,0 +1,425 @@
+package twofactor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/escrow-tf/steam/api"
+	"github.com/escrow-tf/steam/steamid"
+	"github.com/escrow-tf/steam/steamlang"
+	"github.com/escrow-tf/steam/totp"
+	"github.com/rotisserie/eris"
+)
+
+type ConfirmType int
+
+//goland:noinspection GoUnusedConst
+const (
+	UnknownConfirmType ConfirmType = iota
+	SmsConfirmType
+	_
+	EmailConfirmType
+)
+
+// MobileAppAuthenticatorType is the authenticator_type of authenticators added by the Steam mobile app
+const MobileAppAuthenticatorType = 1
+
+type AddAuthenticatorRequest struct {
+	AccessToken string
+	SteamID     steamid.SteamID
+	DeviceId    string
+}
+
+func (r AddAuthenticatorRequest) CacheTTL() time.Duration {
+	return 0
+}
+
+func (r AddAuthenticatorRequest) EnsureResponseSuccess(httpResponse *http.Response) error {
+	return steamlang.EnsureSuccessResponse(httpResponse)
+}
+
+func (r AddAuthenticatorRequest) Headers() (http.Header, error) {
+	return nil, nil
+}
+
+func (r AddAuthenticatorRequest) Retryable() bool {
+	return false
+}
+
+func (r AddAuthenticatorRequest) RequiresApiKey() bool {
+	return false
+}
+
+func (r AddAuthenticatorRequest) Method() string {
+	return http.MethodPost
+}
+
+func (r AddAuthenticatorRequest) OldValues() (url.Values, error) {
+	values := make(url.Values)
+	values.Add("steamid", r.SteamID.String())
+	values.Add("authenticator_type", strconv.Itoa(MobileAppAuthenticatorType))
+	values.Add("device_identifier", r.DeviceId)
+	values.Add("sms_phone_id", "1")
+	values.Add("version", "2")
+	return values, nil
+}
+
+func (r AddAuthenticatorRequest) Values() (url.Values, error) {
+	return r.OldValues()
+}
+
+func (r AddAuthenticatorRequest) Url() string {
+	return fmt.Sprintf(
+		"%v/ITwoFactorService/AddAuthenticator/v1/?access_token=%s",
+		api.BaseURL,
+		url.QueryEscape(r.AccessToken),
+	)
+}
+
+type AddAuthenticatorResponse struct {
+	Response struct {
+		SharedSecret    string            `json:"shared_secret"`
+		SerialNumber    string            `json:"serial_number"`
+		RevocationCode  string            `json:"revocation_code"`
+		Uri             string            `json:"uri"`
+		ServerTime      int64             `json:"server_time,string"`
+		AccountName     string            `json:"account_name"`
+		TokenGid        string            `json:"token_gid"`
+		IdentitySecret  string            `json:"identity_secret"`
+		Secret1         string            `json:"secret_1"`
+		Status          steamlang.EResult `json:"status"`
+		PhoneNumberHint string            `json:"phone_number_hint"`
+		ConfirmType     ConfirmType       `json:"confirm_type"`
+	} `json:"response"`
+}
+
+// AddAuthenticator begins adding a mobile authenticator to the account. The returned secrets are not usable until
+// the authenticator is activated with FinalizeAddAuthenticator, using the code Steam sends by SMS or email as
+// indicated by ConfirmType.
+func (c *Client) AddAuthenticator(
+	ctx context.Context,
+	accessToken string,
+	steamID steamid.SteamID,
+	deviceId string,
+) (*AddAuthenticatorResponse, error) {
+	request := AddAuthenticatorRequest{
+		AccessToken: accessToken,
+		SteamID:     steamID,
+		DeviceId:    deviceId,
+	}
+	var response AddAuthenticatorResponse
+	sendErr := c.Transport.Send(ctx, request, &response)
+	if sendErr != nil {
+		return nil, sendErr
+	}
+
+	if response.Response.Status != steamlang.OKResult {
+		return nil, steamlang.EResultError(response.Response.Status)
+	}
+
+	return &response, nil
+}
+
+type FinalizeAddAuthenticatorRequest struct {
+	AccessToken       string
+	SteamID           steamid.SteamID
+	AuthenticatorCode string
+	AuthenticatorTime time.Time
+	ActivationCode    string
+}
+
+func (r FinalizeAddAuthenticatorRequest) CacheTTL() time.Duration {
+	return 0
+}
+
+func (r FinalizeAddAuthenticatorRequest) EnsureResponseSuccess(httpResponse *http.Response) error {
+	return steamlang.EnsureSuccessResponse(httpResponse)
+}
+
+func (r FinalizeAddAuthenticatorRequest) Headers() (http.Header, error) {
+	return nil, nil
+}
+
+func (r FinalizeAddAuthenticatorRequest) Retryable() bool {
+	return false
+}
+
+func (r FinalizeAddAuthenticatorRequest) RequiresApiKey() bool {
+	return false
+}
+
+func (r FinalizeAddAuthenticatorRequest) Method() string {
+	return http.MethodPost
+}
+
+func (r FinalizeAddAuthenticatorRequest) OldValues() (url.Values, error) {
+	values := make(url.Values)
+	values.Add("steamid", r.SteamID.String())
+	values.Add("authenticator_code", r.AuthenticatorCode)
+	values.Add("authenticator_time", strconv.FormatInt(r.AuthenticatorTime.Unix(), 10))
+	values.Add("activation_code", r.ActivationCode)
+	values.Add("validate_sms_code", "1")
+	return values, nil
+}
+
+func (r FinalizeAddAuthenticatorRequest) Values() (url.Values, error) {
+	return r.OldValues()
+}
+
+func (r FinalizeAddAuthenticatorRequest) Url() string {
+	return fmt.Sprintf(
+		"%v/ITwoFactorService/FinalizeAddAuthenticator/v1/?access_token=%s",
+		api.BaseURL,
+		url.QueryEscape(r.AccessToken),
+	)
+}
+
+type FinalizeAddAuthenticatorResponse struct {
+	Response struct {
+		Status     steamlang.EResult `json:"status"`
+		ServerTime int64             `json:"server_time,string"`
+		WantMore   bool              `json:"want_more"`
+		Success    bool              `json:"success"`
+	} `json:"response"`
+}
+
+// FinalizeAddAuthenticator submits the activation code along with a code generated by the new authenticator. Steam
+// may set WantMore, asking for the code of the following time window, or reject the code with
+// TwoFactorCodeMismatchResult in Status; the caller is expected to retry in both cases.
+func (c *Client) FinalizeAddAuthenticator(
+	ctx context.Context,
+	accessToken string,
+	steamID steamid.SteamID,
+	authenticatorCode string,
+	authenticatorTime time.Time,
+	activationCode string,
+) (*FinalizeAddAuthenticatorResponse, error) {
+	request := FinalizeAddAuthenticatorRequest{
+		AccessToken:       accessToken,
+		SteamID:           steamID,
+		AuthenticatorCode: authenticatorCode,
+		AuthenticatorTime: authenticatorTime,
+		ActivationCode:    activationCode,
+	}
+	var response FinalizeAddAuthenticatorResponse
+	sendErr := c.Transport.Send(ctx, request, &response)
+	if sendErr != nil {
+		return nil, sendErr
+	}
+
+	return &response, nil
+}
+
+type SteamGuardScheme int
+
+//goland:noinspection GoUnusedConst
+const (
+	// EmailSteamGuardScheme returns the account to Steam Guard email codes
+	EmailSteamGuardScheme SteamGuardScheme = 1
+	// NoneSteamGuardScheme removes Steam Guard from the account entirely
+	NoneSteamGuardScheme SteamGuardScheme = 2
+)
+
+type RemoveAuthenticatorRequest struct {
+	AccessToken    string
+	RevocationCode string
+	Scheme         SteamGuardScheme
+}
+
+func (r RemoveAuthenticatorRequest) CacheTTL() time.Duration {
+	return 0
+}
+
+func (r RemoveAuthenticatorRequest) EnsureResponseSuccess(httpResponse *http.Response) error {
+	return steamlang.EnsureSuccessResponse(httpResponse)
+}
+
+func (r RemoveAuthenticatorRequest) Headers() (http.Header, error) {
+	return nil, nil
+}
+
+func (r RemoveAuthenticatorRequest) Retryable() bool {
+	return false
+}
+
+func (r RemoveAuthenticatorRequest) RequiresApiKey() bool {
+	return false
+}
+
+func (r RemoveAuthenticatorRequest) Method() string {
+	return http.MethodPost
+}
+
+func (r RemoveAuthenticatorRequest) OldValues() (url.Values, error) {
+	values := make(url.Values)
+	values.Add("revocation_code", r.RevocationCode)
+	values.Add("steamguard_scheme", strconv.Itoa(int(r.Scheme)))
+	return values, nil
+}
+
+func (r RemoveAuthenticatorRequest) Values() (url.Values, error) {
+	return r.OldValues()
+}
+
+func (r RemoveAuthenticatorRequest) Url() string {
+	return fmt.Sprintf(
+		"%v/ITwoFactorService/RemoveAuthenticator/v1/?access_token=%s",
+		api.BaseURL,
+		url.QueryEscape(r.AccessToken),
+	)
+}
+
+type RemoveAuthenticatorResponse struct {
+	Response struct {
+		Success                     bool `json:"success"`
+		RevocationAttemptsRemaining int  `json:"revocation_attempts_remaining"`
+	} `json:"response"`
+}
+
+// RemoveAuthenticator removes the account's mobile authenticator using its revocation code, switching the account
+// to the given Steam Guard scheme. Steam only allows a few attempts with an incorrect revocation code.
+func (c *Client) RemoveAuthenticator(
+	ctx context.Context,
+	accessToken string,
+	revocationCode string,
+	scheme SteamGuardScheme,
+) (*RemoveAuthenticatorResponse, error) {
+	request := RemoveAuthenticatorRequest{
+		AccessToken:    accessToken,
+		RevocationCode: revocationCode,
+		Scheme:         scheme,
+	}
+	var response RemoveAuthenticatorResponse
+	sendErr := c.Transport.Send(ctx, request, &response)
+	if sendErr != nil {
+		return nil, sendErr
+	}
+
+	if !response.Response.Success {
+		return nil, eris.Errorf(
+			"authenticator was not removed, %d revocation attempts remaining",
+			response.Response.RevocationAttemptsRemaining,
+		)
+	}
+
+	return &response, nil
+}
+
+type AuthenticatorState int
+
+//goland:noinspection GoUnusedConst
+const (
+	NoAuthenticatorState     AuthenticatorState = 0
+	ActiveAuthenticatorState AuthenticatorState = 1
+)
+
+type QueryStatusRequest struct {
+	AccessToken string
+	SteamID     steamid.SteamID
+}
+
+func (r QueryStatusRequest) CacheTTL() time.Duration {
+	return 0
+}
+
+func (r QueryStatusRequest) EnsureResponseSuccess(httpResponse *http.Response) error {
+	return steamlang.EnsureSuccessResponse(httpResponse)
+}
+
+func (r QueryStatusRequest) Headers() (http.Header, error) {
+	return nil, nil
+}
+
+func (r QueryStatusRequest) Retryable() bool {
+	return true
+}
+
+func (r QueryStatusRequest) RequiresApiKey() bool {
+	return false
+}
+
+func (r QueryStatusRequest) Method() string {
+	return http.MethodPost
+}
+
+func (r QueryStatusRequest) OldValues() (url.Values, error) {
+	values := make(url.Values)
+	values.Add("steamid", r.SteamID.String())
+	return values, nil
+}
+
+func (r QueryStatusRequest) Values() (url.Values, error) {
+	return r.OldValues()
+}
+
+func (r QueryStatusRequest) Url() string {
+	return fmt.Sprintf(
+		"%v/ITwoFactorService/QueryStatus/v1/?access_token=%s",
+		api.BaseURL,
+		url.QueryEscape(r.AccessToken),
+	)
+}
+
+type QueryStatusResponse struct {
+	Response struct {
+		State                       AuthenticatorState `json:"state"`
+		AuthenticatorType           int                `json:"authenticator_type"`
+		AuthenticatorAllowed        bool               `json:"authenticator_allowed"`
+		SteamGuardScheme            SteamGuardScheme   `json:"steamguard_scheme"`
+		TokenGid                    string             `json:"token_gid"`
+		EmailValidated              bool               `json:"email_validated"`
+		DeviceIdentifier            string             `json:"device_identifier"`
+		TimeCreated                 int64              `json:"time_created"`
+		RevocationAttemptsRemaining int                `json:"revocation_attempts_remaining"`
+	} `json:"response"`
+}
+
+// AuthenticatorStatus describes the mobile authenticator currently attached to an account.
+type AuthenticatorStatus struct {
+	State                       AuthenticatorState
+	SteamGuardScheme            SteamGuardScheme
+	TokenGid                    string
+	TimeCreated                 time.Time
+	DeviceId                    string
+	RevocationAttemptsRemaining int
+	// DeviceIdMatches is false when the authenticator was enrolled from a device other than the expected one,
+	// which usually means it was replaced and our secrets no longer work
+	DeviceIdMatches bool
+}
+
+// QueryStatus returns the status of the account's mobile authenticator. deviceId is the device ID we expect the
+// authenticator to be enrolled with; when empty, totp.GetDeviceId is used.
+func (c *Client) QueryStatus(
+	ctx context.Context,
+	accessToken string,
+	steamID steamid.SteamID,
+	deviceId string,
+) (*AuthenticatorStatus, error) {
+	request := QueryStatusRequest{
+		AccessToken: accessToken,
+		SteamID:     steamID,
+	}
+	var response QueryStatusResponse
+	sendErr := c.Transport.Send(ctx, request, &response)
+	if sendErr != nil {
+		return nil, sendErr
+	}
+
+	if len(deviceId) == 0 {
+		deviceId = totp.GetDeviceId(steamID.String())
+	}
+
+	return &AuthenticatorStatus{
+		State:                       response.Response.State,
+		SteamGuardScheme:            response.Response.SteamGuardScheme,
+		TokenGid:                    response.Response.TokenGid,
+		TimeCreated:                 time.Unix(response.Response.TimeCreated, 0),
+		DeviceId:                    response.Response.DeviceIdentifier,
+		RevocationAttemptsRemaining: response.Response.RevocationAttemptsRemaining,
+		DeviceIdMatches:             response.Response.DeviceIdentifier == deviceId,
+	}, nil
+}
@@ -4,39 +4,95 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/escrow-tf/steam/api"
 	"github.com/escrow-tf/steam/steamlang"
 )
 
+// DefaultAlignInterval is how often BeginAligning re-aligns with Steam's clock when no interval is given
+const DefaultAlignInterval = time.Hour
+
 type Client struct {
 	Transport api.Transport
 
+	// mutex guards the alignment, which is read by every mobileconf request and replaced by BeginAligning
+	mutex    sync.RWMutex
 	aligned  bool
 	timeDiff time.Duration
 }
 
 func (c *Client) SteamTime() (time.Time, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
 	if !c.aligned {
 		return time.Time{}, errors.New("AlignTime must be called before SteamTime can be retrieved")
 	}
 	return time.Now().UTC().Add(c.timeDiff), nil
 }
 
+// TimeDrift returns how far Steam's clock was ahead of the local clock at the last alignment. It is negative when
+// the local clock is ahead.
+func (c *Client) TimeDrift() (time.Duration, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if !c.aligned {
+		return 0, errors.New("AlignTime must be called before TimeDrift can be retrieved")
+	}
+	return c.timeDiff, nil
+}
+
+// AlignTime measures the difference between the local clock and Steam's using ITwoFactorService/QueryTime. The
+// server time is assumed to have been read halfway through the round trip, compensating for request latency.
 func (c *Client) AlignTime(ctx context.Context) error {
-	//unixNow := time.Now().Unix()
-	//timeResponse, err := c.QueryTime(ctx)
-	//if err != nil {
-	//	return err
-	//}
-	//c.timeDiff = time.Second * time.Duration(timeResponse.Response.ServerTime-unixNow)
+	requestedAt := time.Now()
+	timeResponse, err := c.QueryTime(ctx)
+	if err != nil {
+		return err
+	}
+	respondedAt := time.Now()
+
+	localTime := requestedAt.Add(respondedAt.Sub(requestedAt) / 2)
+	serverTime := time.Unix(timeResponse.Response.ServerTime, 0)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// server_time only has a resolution of a second, so drift under that is indistinguishable from latency
+	c.timeDiff = serverTime.Sub(localTime).Round(time.Second)
 	c.aligned = true
 	return nil
 }
 
+// BeginAligning re-aligns with Steam's clock every interval until ctx is cancelled, correcting for local clock
+// drift over long-running sessions. Failed alignments keep the previous alignment.
+func (c *Client) BeginAligning(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultAlignInterval
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+
+			err := c.AlignTime(ctx)
+			if err != nil {
+				log.Printf("Error aligning time with steam: %v", err)
+			}
+		}
+	}()
+}
+
 type QueryTimeRequest struct{}
 
 func (q QueryTimeRequest) Values() (url.Values, error) {
@@ -0,0 +1,95 @@
+package steam
+
+import (
+	"github.com/escrow-tf/steam/api/auth"
+	steamproto "github.com/escrow-tf/steam/proto/steam"
+	"github.com/escrow-tf/steam/steamid"
+	"github.com/escrow-tf/steam/totp"
+)
+
+// DeviceProfile describes the device a WebSession presents itself as when logging in. Matching the device an
+// authenticator was originally enrolled on keeps logins and confirmations consistent with the Steam mobile app.
+// Fields left at their zero value are taken from DefaultDeviceProfile, except Persistence and Language, whose zero
+// values are an ephemeral session and English, and OsType and GamingDeviceType, which are only defaulted for the
+// MobileApp platform type.
+type DeviceProfile struct {
+	// FriendlyName is shown to the account owner in the list of authorized devices
+	FriendlyName string
+	// PlatformType is one of the MobileApp, WebBrowser or SteamClient platform types
+	PlatformType     steamproto.EAuthTokenPlatformType
+	OsType           int32
+	GamingDeviceType uint32
+	Persistence      steamproto.ESessionPersistence
+	// Language is the ELanguage sent when starting a credential login
+	Language uint32
+	// QosLevel is the qos_level sent when starting a credential login
+	QosLevel int32
+	// DeviceId is sent with mobile confirmation requests and authenticator enrollment. When empty, the device ID
+	// recorded in the AccountState is used, or one is derived from the SteamID.
+	DeviceId string
+}
+
+// DefaultDeviceProfile returns the profile used when Options.DeviceProfile is nil: a persistent login from an
+// Android phone running the Steam mobile app.
+func DefaultDeviceProfile() DeviceProfile {
+	return DeviceProfile{
+		FriendlyName:     "Galaxy S25",
+		PlatformType:     steamproto.EAuthTokenPlatformType_k_EAuthTokenPlatformType_MobileApp,
+		OsType:           auth.AndroidUnknownOsType,
+		GamingDeviceType: auth.DefaultGamingDeviceType,
+		Persistence:      steamproto.ESessionPersistence_k_ESessionPersistence_Persistent,
+		QosLevel:         auth.DefaultQosLevel,
+	}
+}
+
+func (options Options) deviceProfile() DeviceProfile {
+	defaults := DefaultDeviceProfile()
+	if options.DeviceProfile == nil {
+		return defaults
+	}
+
+	profile := *options.DeviceProfile
+	if len(profile.FriendlyName) == 0 {
+		profile.FriendlyName = defaults.FriendlyName
+	}
+	if profile.PlatformType == steamproto.EAuthTokenPlatformType_k_EAuthTokenPlatformType_Unknown {
+		profile.PlatformType = defaults.PlatformType
+	}
+	// the default OsType and GamingDeviceType describe the Android app, so they don't apply to other platforms
+	if profile.PlatformType == steamproto.EAuthTokenPlatformType_k_EAuthTokenPlatformType_MobileApp {
+		if profile.OsType == 0 {
+			profile.OsType = defaults.OsType
+		}
+		if profile.GamingDeviceType == 0 {
+			profile.GamingDeviceType = defaults.GamingDeviceType
+		}
+	}
+	if profile.QosLevel == 0 {
+		profile.QosLevel = defaults.QosLevel
+	}
+
+	return profile
+}
+
+func (profile DeviceProfile) deviceDetails() auth.DeviceDetails {
+	return auth.DeviceDetails{
+		FriendlyName:     profile.FriendlyName,
+		PlatformType:     profile.PlatformType,
+		OsType:           profile.OsType,
+		GamingDeviceType: profile.GamingDeviceType,
+	}
+}
+
+// deviceId returns the profile's device ID, falling back to the one recorded in state and then to the ID derived
+// from steamID.
+func (profile DeviceProfile) deviceId(state *AccountState, steamID steamid.SteamID) string {
+	if len(profile.DeviceId) != 0 {
+		return profile.DeviceId
+	}
+
+	if state != nil && len(state.deviceId) != 0 {
+		return state.deviceId
+	}
+
+	return totp.GetDeviceId(steamID.String())
+}
@@ -0,0 +1,161 @@
+package steam
+
+import (
+	"context"
+	"time"
+
+	"github.com/escrow-tf/steam/api/twofactor"
+	"github.com/escrow-tf/steam/steamlang"
+	"github.com/escrow-tf/steam/totp"
+	"github.com/rotisserie/eris"
+)
+
+// finalizeAuthenticatorAttempts bounds how many codes FinalizeAuthenticator submits before giving up
+const finalizeAuthenticatorAttempts = 30
+
+// PendingAuthenticator is a mobile authenticator that was added to the account but not yet activated. Its
+// RevocationCode should be stored before calling FinalizeAuthenticator, as it is the only way to remove the
+// authenticator if the secrets are lost.
+type PendingAuthenticator struct {
+	AccountName     string
+	DeviceId        string
+	SharedSecret    string
+	IdentitySecret  string
+	RevocationCode  string
+	SerialNumber    string
+	Uri             string
+	TokenGid        string
+	Secret1         string
+	ServerTime      int64
+	ConfirmType     twofactor.ConfirmType
+	PhoneNumberHint string
+}
+
+// AddAuthenticator adds a mobile authenticator to the account, using the session's device ID. Steam then sends an
+// activation code by SMS or email, as indicated by ConfirmType, which must be passed to FinalizeAuthenticator.
+func (w *WebSession) AddAuthenticator(ctx context.Context) (*PendingAuthenticator, error) {
+	deviceId := w.deviceProfile.deviceId(w.state, w.steamId)
+	response, err := w.twoFactorClient.AddAuthenticator(ctx, w.AccessToken(), w.steamId, deviceId)
+	if err != nil {
+		return nil, eris.Wrap(err, "AddAuthenticator failed")
+	}
+
+	return &PendingAuthenticator{
+		AccountName:     response.Response.AccountName,
+		DeviceId:        deviceId,
+		SharedSecret:    response.Response.SharedSecret,
+		IdentitySecret:  response.Response.IdentitySecret,
+		RevocationCode:  response.Response.RevocationCode,
+		SerialNumber:    response.Response.SerialNumber,
+		Uri:             response.Response.Uri,
+		TokenGid:        response.Response.TokenGid,
+		Secret1:         response.Response.Secret1,
+		ServerTime:      response.Response.ServerTime,
+		ConfirmType:     response.Response.ConfirmType,
+		PhoneNumberHint: response.Response.PhoneNumberHint,
+	}, nil
+}
+
+// FinalizeAuthenticator activates a pending authenticator with the activation code Steam sent, and returns an
+// AccountState holding its secrets. Codes are generated from the new shared secret, moving on to the next time
+// window when Steam asks for more codes and re-aligning time when a code is rejected.
+func (w *WebSession) FinalizeAuthenticator(
+	ctx context.Context,
+	pending *PendingAuthenticator,
+	activationCode string,
+) (*AccountState, error) {
+	totpState, err := totp.NewState(pending.SharedSecret, pending.IdentitySecret)
+	if err != nil {
+		return nil, eris.Errorf("pending authenticator had invalid secrets: %v", err)
+	}
+
+	steamTime, err := w.twoFactorClient.SteamTime()
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; attempt < finalizeAuthenticatorAttempts; attempt++ {
+		code, codeErr := totpState.GenerateTotpCode("conf", steamTime)
+		if codeErr != nil {
+			return nil, eris.Errorf("error generating totp code failed: %v", codeErr)
+		}
+
+		response, finalizeErr := w.twoFactorClient.FinalizeAddAuthenticator(
+			ctx,
+			w.AccessToken(),
+			w.steamId,
+			code,
+			steamTime,
+			activationCode,
+		)
+		if finalizeErr != nil {
+			return nil, eris.Wrap(finalizeErr, "FinalizeAddAuthenticator failed")
+		}
+
+		switch {
+		case response.Response.Status == steamlang.TwoFactorCodeMismatchResult:
+			// our clock has likely drifted from steam's since the last alignment
+			alignErr := w.twoFactorClient.AlignTime(ctx)
+			if alignErr != nil {
+				return nil, eris.Wrap(alignErr, "twoFactorClient.AlignTime() failed")
+			}
+
+			steamTime, err = w.twoFactorClient.SteamTime()
+			if err != nil {
+				return nil, err
+			}
+		case response.Response.Status != steamlang.OKResult:
+			return nil, eris.Wrap(steamlang.EResultError(response.Response.Status), "FinalizeAddAuthenticator failed")
+		case response.Response.WantMore:
+			steamTime = steamTime.Add(30 * time.Second)
+		case response.Response.Success:
+			return w.enrolledAccountState(pending, totpState), nil
+		default:
+			return nil, eris.New("FinalizeAddAuthenticator was neither successful nor wanted more codes")
+		}
+	}
+
+	return nil, eris.Errorf("authenticator was not activated after %d codes", finalizeAuthenticatorAttempts)
+}
+
+func (w *WebSession) enrolledAccountState(pending *PendingAuthenticator, totpState *totp.State) *AccountState {
+	accountState := &AccountState{
+		accountName:    pending.AccountName,
+		totpState:      totpState,
+		deviceId:       pending.DeviceId,
+		revocationCode: pending.RevocationCode,
+	}
+
+	if w.state != nil {
+		accountState.accountName = w.state.accountName
+		accountState.password = w.state.password
+	}
+
+	return accountState
+}
+
+// RemoveAuthenticator removes the account's mobile authenticator using the revocation code recorded in the
+// AccountState, switching the account to the given Steam Guard scheme.
+func (w *WebSession) RemoveAuthenticator(ctx context.Context, scheme twofactor.SteamGuardScheme) error {
+	if w.state == nil || len(w.state.revocationCode) == 0 {
+		return eris.New("removing the authenticator requires a revocation code in AccountState")
+	}
+
+	_, err := w.twoFactorClient.RemoveAuthenticator(ctx, w.AccessToken(), w.state.revocationCode, scheme)
+	if err != nil {
+		return eris.Wrap(err, "RemoveAuthenticator failed")
+	}
+
+	return nil
+}
+
+// AuthenticatorStatus queries the account's mobile authenticator, comparing its device ID with the session's.
+func (w *WebSession) AuthenticatorStatus(ctx context.Context) (*twofactor.AuthenticatorStatus, error) {
+	deviceId := w.deviceProfile.deviceId(w.state, w.steamId)
+	status, err := w.twoFactorClient.QueryStatus(ctx, w.AccessToken(), w.steamId, deviceId)
+	if err != nil {
+		return nil, eris.Wrap(err, "QueryStatus failed")
+	}
+
+	return status, nil
+}
@@ -0,0 +1,89 @@
+package steam
+
+import (
+	"context"
+
+	steamproto "github.com/escrow-tf/steam/proto/steam"
+	"github.com/rotisserie/eris"
+)
+
+// PendingLogin is a login attempt made elsewhere that is waiting for this account's mobile authenticator to approve
+// or deny it.
+type PendingLogin struct {
+	ClientId                  uint64
+	Version                   int32
+	Ip                        string
+	City                      string
+	State                     string
+	Country                   string
+	DeviceFriendlyName        string
+	PlatformType              steamproto.EAuthTokenPlatformType
+	RequestedPersistence      steamproto.ESessionPersistence
+	RequestorLocationMismatch bool
+	HighUsageLogin            bool
+}
+
+// PendingLogins lists the login attempts awaiting approval from this account's mobile authenticator.
+func (w *WebSession) PendingLogins(ctx context.Context) ([]PendingLogin, error) {
+	clientIds, err := w.authClient.GetAuthSessionsForAccount(ctx, w.AccessToken())
+	if err != nil {
+		return nil, eris.Wrap(err, "GetAuthSessionsForAccount failed")
+	}
+
+	pendingLogins := make([]PendingLogin, 0, len(clientIds))
+	for _, clientId := range clientIds {
+		info, infoErr := w.authClient.GetAuthSessionInfo(ctx, w.AccessToken(), clientId)
+		if infoErr != nil {
+			return nil, eris.Errorf("GetAuthSessionInfo failed for client %d: %v", clientId, infoErr)
+		}
+
+		pendingLogins = append(pendingLogins, PendingLogin{
+			ClientId:                  clientId,
+			Version:                   info.GetVersion(),
+			Ip:                        info.GetIp(),
+			City:                      info.GetCity(),
+			State:                     info.GetState(),
+			Country:                   info.GetCountry(),
+			DeviceFriendlyName:        info.GetDeviceFriendlyName(),
+			PlatformType:              info.GetPlatformType(),
+			RequestedPersistence:      info.GetRequestedPersistence(),
+			RequestorLocationMismatch: info.GetRequestorLocationMismatch(),
+			HighUsageLogin:            info.GetHighUsageLogin(),
+		})
+	}
+
+	return pendingLogins, nil
+}
+
+// ApproveLogin allows the login attempt to complete, as if it were approved in the Steam mobile app.
+func (w *WebSession) ApproveLogin(ctx context.Context, login PendingLogin) error {
+	return w.respondToLogin(ctx, login, true)
+}
+
+// DenyLogin rejects the login attempt.
+func (w *WebSession) DenyLogin(ctx context.Context, login PendingLogin) error {
+	return w.respondToLogin(ctx, login, false)
+}
+
+func (w *WebSession) respondToLogin(ctx context.Context, login PendingLogin, confirm bool) error {
+	if w.state == nil || !w.state.totpState.HasSharedSecret() {
+		return eris.New("responding to a login requires a shared secret in AccountState")
+	}
+
+	signature := w.state.totpState.GenerateSessionSignature(login.Version, login.ClientId, w.steamId.ID())
+	err := w.authClient.UpdateAuthSessionWithMobileConfirmation(
+		ctx,
+		w.AccessToken(),
+		login.Version,
+		login.ClientId,
+		w.steamId,
+		signature,
+		confirm,
+		login.RequestedPersistence,
+	)
+	if err != nil {
+		return eris.Wrap(err, "UpdateAuthSessionWithMobileConfirmation failed")
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package steam
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	steamproto "github.com/escrow-tf/steam/proto/steam"
+	"github.com/rotisserie/eris"
+)
+
+// LoggedOutError is returned when renewing the tokens of a session that was logged out
+var LoggedOutError = errors.New("the session was logged out")
+
+// Logout revokes the session's refresh token, clears its login cookies and stops BeginPolling. The session can't be
+// used or resumed afterward.
+func (w *WebSession) Logout(ctx context.Context) error {
+	err := w.authClient.RevokeToken(
+		ctx,
+		w.AccessToken(),
+		w.RefreshToken(),
+		steamproto.EAuthTokenRevokeAction_k_EAuthTokenRevokeLogout,
+	)
+	if err != nil {
+		return eris.Wrap(err, "RevokeToken failed")
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.accessToken = ""
+	w.refreshToken = ""
+	w.jwt = nil
+	w.loggedOut = true
+	if w.stopPolling != nil {
+		w.stopPolling()
+		w.stopPolling = nil
+	}
+
+	for _, host := range cookieHosts {
+		cookieUrl := &url.URL{Scheme: "https", Host: host, Path: "/"}
+		w.transport.CookieJar().SetCookies(cookieUrl, []*http.Cookie{
+			{
+				Name:   "steamLoginSecure",
+				MaxAge: -1,
+			},
+			{
+				Name:   "sessionid",
+				MaxAge: -1,
+			},
+		})
+	}
+
+	return nil
+}
+
+// RevokeOtherSessions permanently revokes every refresh token issued for the account except the one this session
+// is using, logging out all other browsers, apps and bots. It returns the number of tokens revoked. Revocations are
+// signed with the shared secret when AccountState has one.
+func (w *WebSession) RevokeOtherSessions(ctx context.Context) (int, error) {
+	accessToken := w.AccessToken()
+	tokens, err := w.authClient.EnumerateRefreshTokens(ctx, accessToken, false)
+	if err != nil {
+		return 0, eris.Wrap(err, "EnumerateRefreshTokens failed")
+	}
+
+	revoked := 0
+	for _, token := range tokens.GetRefreshTokens() {
+		if token.GetTokenId() == tokens.GetRequestingToken() {
+			continue
+		}
+
+		switch token.GetEffectiveTokenState() {
+		case steamproto.EAuthTokenState_k_EAuthTokenState_Revoked,
+			steamproto.EAuthTokenState_k_EAuthTokenState_LoggedOut,
+			steamproto.EAuthTokenState_k_EAuthTokenState_Denied:
+			continue
+		}
+
+		var signature []byte
+		if w.state != nil && w.state.totpState.HasSharedSecret() {
+			signature = w.state.totpState.GenerateTokenRevokeSignature(token.GetTokenId())
+		}
+
+		err = w.authClient.RevokeRefreshToken(
+			ctx,
+			accessToken,
+			token.GetTokenId(),
+			w.steamId,
+			steamproto.EAuthTokenRevokeAction_k_EAuthTokenRevokePermanent,
+			signature,
+		)
+		if err != nil {
+			return revoked, eris.Errorf("RevokeRefreshToken failed for token %d: %v", token.GetTokenId(), err)
+		}
+
+		revoked++
+	}
+
+	return revoked, nil
+}
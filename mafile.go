@@ -0,0 +1,213 @@
+package steam
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+
+	"github.com/escrow-tf/steam/steamid"
+	"github.com/rotisserie/eris"
+)
+
+// encrypted maFiles use the parameters of SteamDesktopAuthenticator's FileEncryptor
+const (
+	maFilePbkdf2Iterations = 50000
+	maFileSaltLength       = 8
+	maFileKeyLength        = 32
+)
+
+// MaFile is an authenticator in the .maFile format written by SteamDesktopAuthenticator and compatible tools.
+type MaFile struct {
+	SharedSecret   string        `json:"shared_secret"`
+	SerialNumber   string        `json:"serial_number,omitempty"`
+	RevocationCode string        `json:"revocation_code"`
+	Uri            string        `json:"uri,omitempty"`
+	ServerTime     int64         `json:"server_time,omitempty"`
+	AccountName    string        `json:"account_name"`
+	TokenGid       string        `json:"token_gid,omitempty"`
+	IdentitySecret string        `json:"identity_secret"`
+	Secret1        string        `json:"secret_1,omitempty"`
+	Status         int           `json:"status,omitempty"`
+	DeviceId       string        `json:"device_id"`
+	FullyEnrolled  bool          `json:"fully_enrolled"`
+	Session        MaFileSession `json:"Session"`
+}
+
+type MaFileSession struct {
+	SteamID      uint64 `json:"SteamID"`
+	SessionID    string `json:"SessionID,omitempty"`
+	AccessToken  string `json:"AccessToken,omitempty"`
+	RefreshToken string `json:"RefreshToken,omitempty"`
+}
+
+// MaFileManifest is the manifest.json kept alongside a directory of maFiles. When Encrypted is set, each entry holds
+// the salt and IV its maFile was encrypted with.
+type MaFileManifest struct {
+	Encrypted bool                  `json:"encrypted"`
+	Entries   []MaFileManifestEntry `json:"entries"`
+}
+
+type MaFileManifestEntry struct {
+	EncryptionIV   string `json:"encryption_iv"`
+	EncryptionSalt string `json:"encryption_salt"`
+	Filename       string `json:"filename"`
+	SteamID        uint64 `json:"steamid"`
+}
+
+func ParseMaFile(data []byte) (*MaFile, error) {
+	var maFile MaFile
+	err := json.Unmarshal(data, &maFile)
+	if err != nil {
+		return nil, eris.Errorf("maFile was invalid JSON: %v", err)
+	}
+
+	if len(maFile.SharedSecret) == 0 {
+		return nil, eris.New("maFile was missing shared_secret")
+	}
+
+	return &maFile, nil
+}
+
+// ParseEncryptedMaFile decrypts and parses an maFile encrypted with passkey, using the salt and IV from its
+// manifest entry.
+func ParseEncryptedMaFile(data []byte, passkey string, entry MaFileManifestEntry) (*MaFile, error) {
+	plaintext, err := DecryptMaFile(data, passkey, entry.EncryptionSalt, entry.EncryptionIV)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseMaFile(plaintext)
+}
+
+// AccountState creates an AccountState from the maFile's secrets. The maFile's device_id is kept, rather than being
+// derived from the SteamID.
+func (m *MaFile) AccountState(password string) (*AccountState, error) {
+	accountState, err := NewAccountState(m.AccountName, password, m.SharedSecret, m.IdentitySecret)
+	if err != nil {
+		return nil, err
+	}
+
+	accountState.deviceId = m.DeviceId
+	accountState.revocationCode = m.RevocationCode
+	return accountState, nil
+}
+
+// SteamId returns the SteamID stored in the maFile's session.
+func (m *MaFile) SteamId() (steamid.SteamID, error) {
+	return steamid.ParseSteamID64(strconv.FormatUint(m.Session.SteamID, 10))
+}
+
+// MaFile creates an maFile holding the account's secrets, which can be written with json.Marshal.
+func (accountState *AccountState) MaFile(steamID steamid.SteamID) *MaFile {
+	return &MaFile{
+		SharedSecret:   accountState.totpState.SharedSecret(),
+		RevocationCode: accountState.revocationCode,
+		AccountName:    accountState.accountName,
+		IdentitySecret: accountState.totpState.IdentitySecret(),
+		DeviceId:       accountState.deviceId,
+		FullyEnrolled:  true,
+		Session: MaFileSession{
+			SteamID: steamID.ID(),
+		},
+	}
+}
+
+// DecryptMaFile decrypts an maFile encrypted by SteamDesktopAuthenticator. The key is derived from passkey and the
+// base64 encoded salt with PBKDF2-SHA1, and the base64 encoded data is decrypted with AES-256-CBC.
+func DecryptMaFile(data []byte, passkey string, salt string, iv string) ([]byte, error) {
+	block, err := maFileCipher(passkey, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	ivBytes, err := base64.StdEncoding.DecodeString(iv)
+	if err != nil {
+		return nil, eris.Errorf("error decoding maFile iv: %v", err)
+	}
+
+	if len(ivBytes) != aes.BlockSize {
+		return nil, eris.Errorf("maFile iv was %d bytes, expected %d", len(ivBytes), aes.BlockSize)
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil {
+		return nil, eris.Errorf("error decoding encrypted maFile: %v", err)
+	}
+
+	if len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, eris.New("encrypted maFile was not a whole number of blocks")
+	}
+
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, ivBytes).CryptBlocks(plaintext, ciphertext)
+
+	// an incorrect passkey almost always shows up as invalid padding
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize {
+		return nil, eris.New("error decrypting maFile, passkey is probably incorrect")
+	}
+
+	for _, b := range plaintext[len(plaintext)-padding:] {
+		if int(b) != padding {
+			return nil, eris.New("error decrypting maFile, passkey is probably incorrect")
+		}
+	}
+
+	return plaintext[:len(plaintext)-padding], nil
+}
+
+// EncryptMaFile encrypts an maFile the way SteamDesktopAuthenticator does, generating a new salt and IV. The
+// returned salt and IV are base64 encoded, ready for the file's MaFileManifestEntry.
+func EncryptMaFile(plaintext []byte, passkey string) (data []byte, salt string, iv string, err error) {
+	saltBytes := make([]byte, maFileSaltLength)
+	if _, err = rand.Read(saltBytes); err != nil {
+		return nil, "", "", eris.Errorf("error creating maFile salt: %v", err)
+	}
+
+	ivBytes := make([]byte, aes.BlockSize)
+	if _, err = rand.Read(ivBytes); err != nil {
+		return nil, "", "", eris.Errorf("error creating maFile iv: %v", err)
+	}
+
+	salt = base64.StdEncoding.EncodeToString(saltBytes)
+	iv = base64.StdEncoding.EncodeToString(ivBytes)
+
+	block, err := maFileCipher(passkey, salt)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	padded := append(bytes.Clone(plaintext), bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	ciphertext := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, ivBytes).CryptBlocks(ciphertext, padded)
+
+	data = []byte(base64.StdEncoding.EncodeToString(ciphertext))
+	return data, salt, iv, nil
+}
+
+func maFileCipher(passkey string, salt string) (cipher.Block, error) {
+	saltBytes, err := base64.StdEncoding.DecodeString(salt)
+	if err != nil {
+		return nil, eris.Errorf("error decoding maFile salt: %v", err)
+	}
+
+	key, err := pbkdf2.Key(sha1.New, passkey, saltBytes, maFilePbkdf2Iterations, maFileKeyLength)
+	if err != nil {
+		return nil, eris.Errorf("error deriving maFile key: %v", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, eris.Errorf("error creating maFile cipher: %v", err)
+	}
+
+	return block, nil
+}
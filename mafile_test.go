@@ -0,0 +1,68 @@
+package steam
+
+import (
+	"bytes"
+	"testing"
+)
+
+//goland:noinspection SpellCheckingInspection
+const testMaFile = `{
+	"shared_secret": "cnOgv/KdpLoP6Nbh0GMkXkPXALQ=",
+	"revocation_code": "R12345",
+	"account_name": "testaccount",
+	"identity_secret": "aWRlbnRpdHlzZWNyZXQ=",
+	"device_id": "android:0b3e2d5a-0000-0000-0000-000000000000",
+	"fully_enrolled": true,
+	"Session": {"SteamID": 76561197960287930}
+}`
+
+func TestParseMaFile(t *testing.T) {
+	maFile, err := ParseMaFile([]byte(testMaFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accountState, err := maFile.AccountState("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if accountState.DeviceId() != maFile.DeviceId {
+		t.Errorf("DeviceId()=%s, expected %s", accountState.DeviceId(), maFile.DeviceId)
+	}
+
+	steamID, err := maFile.SteamId()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exported := accountState.MaFile(steamID)
+	if exported.SharedSecret != maFile.SharedSecret || exported.IdentitySecret != maFile.IdentitySecret {
+		t.Errorf("exported secrets did not match imported secrets")
+	}
+
+	if exported.Session.SteamID != maFile.Session.SteamID {
+		t.Errorf("exported SteamID=%d, expected %d", exported.Session.SteamID, maFile.Session.SteamID)
+	}
+}
+
+func TestEncryptMaFile(t *testing.T) {
+	encrypted, salt, iv, err := EncryptMaFile([]byte(testMaFile), "passkey")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decrypted, err := DecryptMaFile(encrypted, "passkey", salt, iv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(decrypted, []byte(testMaFile)) {
+		t.Errorf("decrypted maFile did not match original")
+	}
+
+	decrypted, err = DecryptMaFile(encrypted, "wrong passkey", salt, iv)
+	if err == nil && bytes.Equal(decrypted, []byte(testMaFile)) {
+		t.Errorf("maFile was decrypted with the wrong passkey")
+	}
+}
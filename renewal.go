@@ -0,0 +1,107 @@
+package steam
+
+import (
+	"context"
+	"time"
+
+	"github.com/escrow-tf/steam/api/auth"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rotisserie/eris"
+)
+
+// RefreshTokenRenewedFunc is called after Steam rotates a session's refresh token, or issues a new one when an
+// expired session logs in again, so that a stored copy can be replaced.
+type RefreshTokenRenewedFunc func(refreshToken string)
+
+const (
+	// accessTokenRenewalMargin is how long before the access token's expiry it is renewed
+	accessTokenRenewalMargin = 10 * time.Minute
+	// refreshTokenRenewalMargin is how long before the refresh token's expiry we start asking for a new one
+	refreshTokenRenewalMargin = 24 * time.Hour
+	// renewalRetryInterval is how long BeginPolling waits after a failed renewal
+	renewalRetryInterval = time.Minute
+)
+
+// RenewTokens generates a new access token and rewrites the steamLoginSecure cookie with it. Renewal of the refresh
+// token is also requested, which Steam only grants as the refresh token approaches its expiry; when it does, the
+// new refresh token replaces the old one and Options.RefreshTokenRenewedFunc is called.
+func (w *WebSession) RenewTokens(ctx context.Context) error {
+	w.mutex.RLock()
+	refreshToken := w.refreshToken
+	loggedOut := w.loggedOut
+	w.mutex.RUnlock()
+
+	if loggedOut {
+		return LoggedOutError
+	}
+
+	response, err := w.authClient.GenerateAccessTokenForApp(ctx, refreshToken, true)
+	if err != nil {
+		return eris.Wrap(err, "GenerateAccessTokenForApp failed")
+	}
+
+	if len(response.GetAccessToken()) == 0 {
+		return eris.New("GenerateAccessTokenForApp did not return an access token")
+	}
+
+	var refreshTokenJwt *jwt.Token
+	renewedRefreshToken := response.GetRefreshToken()
+	rotated := len(renewedRefreshToken) != 0 && renewedRefreshToken != refreshToken
+	if rotated {
+		refreshTokenJwt, _, err = jwt.NewParser().ParseUnverified(renewedRefreshToken, jwt.MapClaims{})
+		if err != nil {
+			return eris.Errorf("renewed refresh token was invalid JWT: %v", err)
+		}
+	}
+
+	w.mutex.Lock()
+	w.accessToken = response.GetAccessToken()
+	if rotated {
+		w.refreshToken = renewedRefreshToken
+		w.jwt = refreshTokenJwt
+	}
+	w.setLoginCookie()
+	w.mutex.Unlock()
+
+	if rotated && w.refreshTokenRenewedFunc != nil {
+		w.refreshTokenRenewedFunc(renewedRefreshToken)
+	}
+
+	return nil
+}
+
+// nextRenewal returns when the session's tokens should next be renewed. See renewalTime.
+func (w *WebSession) nextRenewal() (time.Time, error) {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	return renewalTime(w.accessToken, w.jwt, time.Now())
+}
+
+// renewalTime returns when the tokens should next be renewed, based on the exp claims of the access and refresh
+// tokens. An access token that can't be decoded is an error, rather than a reason to renew immediately.
+func renewalTime(accessToken string, refreshJwt *jwt.Token, now time.Time) (time.Time, error) {
+	accessJwt, err := auth.DecodeSimpleJwt(accessToken)
+	if err != nil {
+		return time.Time{}, eris.Wrap(err, "access token was invalid JWT")
+	}
+
+	if accessJwt.Exp == 0 {
+		return time.Time{}, eris.New("access token was missing expiration claim")
+	}
+
+	renewAt := time.Unix(accessJwt.Exp, 0).Add(-accessTokenRenewalMargin)
+
+	if refreshJwt != nil {
+		refreshExpiration, expErr := refreshJwt.Claims.GetExpirationTime()
+		if expErr == nil && refreshExpiration != nil {
+			// once inside the margin, renewal is requested alongside every access token renewal instead
+			refreshRenewAt := refreshExpiration.Time.Add(-refreshTokenRenewalMargin)
+			if refreshRenewAt.Before(renewAt) && refreshRenewAt.After(now) {
+				renewAt = refreshRenewAt
+			}
+		}
+	}
+
+	return renewAt, nil
+}
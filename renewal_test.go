@@ -0,0 +1,59 @@
+package steam
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func testJwt(t *testing.T, expiration time.Time) (string, *jwt.Token) {
+	t.Helper()
+
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "76561198006409530",
+		"exp": expiration.Unix(),
+	}).SignedString([]byte("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tokenString, token
+}
+
+func TestRenewalTime(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	accessExpiration := now.Add(time.Hour)
+	accessToken, _ := testJwt(t, accessExpiration)
+
+	// a refresh token far from expiry doesn't bring the renewal forward
+	_, refreshJwt := testJwt(t, now.Add(30*24*time.Hour))
+	renewAt, err := renewalTime(accessToken, refreshJwt, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := accessExpiration.Add(-accessTokenRenewalMargin); !renewAt.Equal(expected) {
+		t.Errorf("renewAt=%v, expected %v", renewAt, expected)
+	}
+
+	// a refresh token entering its renewal margin before the access token is renewed is renewed first
+	refreshExpiration := now.Add(refreshTokenRenewalMargin + 30*time.Minute)
+	_, refreshJwt = testJwt(t, refreshExpiration)
+	renewAt, err = renewalTime(accessToken, refreshJwt, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := refreshExpiration.Add(-refreshTokenRenewalMargin); !renewAt.Equal(expected) {
+		t.Errorf("renewAt=%v, expected %v", renewAt, expected)
+	}
+
+	_, err = renewalTime("not a jwt", refreshJwt, now)
+	if err == nil {
+		t.Error("expected an error for an access token that isn't a JWT")
+	}
+}
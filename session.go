@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/escrow-tf/steam/api"
@@ -28,9 +29,11 @@ import (
 )
 
 type AccountState struct {
-	accountName string
-	password    string
-	totpState   *totp.State
+	accountName    string
+	password       string
+	totpState      *totp.State
+	deviceId       string
+	revocationCode string
 }
 
 func NewAccountState(
@@ -55,6 +58,16 @@ func (accountState *AccountState) TotpState() *totp.State {
 	return accountState.totpState
 }
 
+// DeviceId returns the device ID the account's authenticator was enrolled with, if known.
+func (accountState *AccountState) DeviceId() string {
+	return accountState.deviceId
+}
+
+// RevocationCode returns the code that removes the account's authenticator, if known.
+func (accountState *AccountState) RevocationCode() string {
+	return accountState.revocationCode
+}
+
 type WebSession struct {
 	state            *AccountState
 	transport        *api.HttpTransport
@@ -66,21 +79,44 @@ type WebSession struct {
 	tradeOfferClient *tradeoffer.Client
 	twoFactorClient  *twofactor.Client
 
-	clientId        uint64
-	requestId       []byte
-	steamId         steamid.SteamID
-	jwt             *jwt.Token
-	refreshToken    string
-	accessToken     string
-	refreshInterval int
+	deviceProfile           DeviceProfile
+	guardCodeFunc           GuardCodeFunc
+	refreshTokenRenewedFunc RefreshTokenRenewedFunc
+
+	// mutex guards the tokens, which are replaced in the background by BeginPolling
+	mutex        sync.RWMutex
+	clientId     uint64
+	requestId    []byte
+	steamId      steamid.SteamID
+	jwt          *jwt.Token
+	refreshToken string
+	accessToken  string
+	// stopPolling cancels the renewal loop started by BeginPolling
+	stopPolling context.CancelFunc
+	loggedOut   bool
+
+	// reauthMutex lets only one caller at a time restore an expired session
+	reauthMutex sync.Mutex
 }
 
 func (w *WebSession) Transport() api.Transport {
 	return w.transport
 }
 
+// GuardCodeFunc supplies a Steam Guard code that can't be generated locally, such as the code Steam emails to
+// accounts without a mobile authenticator. associatedMessage is the hint Steam returned for the confirmation,
+// usually the domain of the email address the code was sent to.
+type GuardCodeFunc func(ctx context.Context, guardType auth.GuardType, associatedMessage string) (string, error)
+
 type Options struct {
-	AccountState *AccountState
+	// AccountState holds the account's credentials and secrets. It is required by Authenticate and optional
+	// elsewhere; without it, the session can't act on mobile confirmations.
+	AccountState            *AccountState
+	GuardCodeFunc           GuardCodeFunc
+	RefreshTokenRenewedFunc RefreshTokenRenewedFunc
+	// DeviceProfile is the device to log in as. DefaultDeviceProfile is used when nil, and fills in any fields left
+	// unset.
+	DeviceProfile *DeviceProfile
 	api.HttpTransportOptions
 }
 
@@ -94,90 +130,298 @@ func Authenticate(ctx context.Context, options Options) (*WebSession, error) {
 	//	return nil, eris.Errorf("os.Hostname() failed: %v", err)
 	//}
 
-	webTransport := api.NewTransport(options.HttpTransportOptions)
-	authClient := &auth.Client{
-		Transport: webTransport,
+	webTransport, authClient, twoFactorClient, err := newSessionClients(ctx, options)
+	if err != nil {
+		return nil, err
 	}
-	twoFactorClient := &twofactor.Client{
-		Transport: webTransport,
+
+	sessionResponse, steamID, err := startCredentialSession(ctx, options, authClient, twoFactorClient)
+	if err != nil {
+		return nil, err
 	}
 
-	alignErr := twoFactorClient.AlignTime(ctx)
-	if alignErr != nil {
-		return nil, eris.Errorf("twoFactorClient.AlignTime() failed: %v", alignErr)
+	webSession, err := newWebSession(options, webTransport, authClient, twoFactorClient, steamID)
+	if err != nil {
+		return nil, err
 	}
 
+	webSession.clientId = sessionResponse.GetClientId()
+	webSession.requestId = sessionResponse.GetRequestId()
+
+	err = webSession.pollSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return webSession, nil
+}
+
+// startCredentialSession begins an auth session with the account's credentials and completes its Steam Guard
+// confirmation. The session still has to be polled for its tokens.
+func startCredentialSession(
+	ctx context.Context,
+	options Options,
+	authClient *auth.Client,
+	twoFactorClient *twofactor.Client,
+) (*steamproto.CAuthentication_BeginAuthSessionViaCredentials_Response, steamid.SteamID, error) {
 	encryptedPassword, err := authClient.EncryptAccountPassword(
 		ctx,
 		options.AccountState.accountName,
 		options.AccountState.password,
 	)
 	if err != nil {
-		return nil, eris.Errorf("EncryptPassword failed %v", err)
+		return nil, steamid.SteamID{}, eris.Wrap(err, "EncryptPassword failed")
 	}
 
-	deviceDetails := auth.DeviceDetails{
-		//FriendlyName:     fmt.Sprintf("%s (steamguard-cli)", deviceHostName),
-		FriendlyName:     fmt.Sprintf("Galaxy S25"),
-		PlatformType:     steamproto.EAuthTokenPlatformType_k_EAuthTokenPlatformType_MobileApp,
-		OsType:           auth.AndroidUnknownOsType,
-		GamingDeviceType: auth.DefaultGamingDeviceType,
-	}
+	deviceProfile := options.deviceProfile()
 
 	sessionResponse, err := authClient.StartSessionWithCredentials(
 		ctx,
 		options.AccountState.accountName,
 		encryptedPassword,
-		deviceDetails,
+		deviceProfile.deviceDetails(),
+		deviceProfile.Persistence,
+		deviceProfile.Language,
+		deviceProfile.QosLevel,
 	)
 	if err != nil {
-		return nil, eris.Errorf("StartSessionWithCredentials failed %v", err)
-	}
-
-	hasDeviceCodeType := false
-	for _, allowedConfirmation := range sessionResponse.AllowedConfirmations {
-		if allowedConfirmation.ConfirmationType != nil && *allowedConfirmation.ConfirmationType == steamproto.EAuthSessionGuardType_k_EAuthSessionGuardType_DeviceCode {
-			hasDeviceCodeType = true
-		}
+		return nil, steamid.SteamID{}, eris.Wrap(err, "StartSessionWithCredentials failed")
 	}
 
-	if !hasDeviceCodeType {
-		return nil, eris.Errorf("DeviceCode auth not in list of allowed confirmations")
+	guardType, associatedMessage, err := selectGuardType(sessionResponse.GetAllowedConfirmations(), options)
+	if err != nil {
+		return nil, steamid.SteamID{}, err
 	}
 
 	weakToken, _, err := jwt.NewParser().ParseUnverified(*sessionResponse.WeakToken, jwt.MapClaims{})
 	if err != nil {
-		return nil, eris.Errorf("weak token was invalid JWT, credentials probably incorrect: %v", err)
+		return nil, steamid.SteamID{}, eris.Errorf(
+			"weak token was invalid JWT, credentials probably incorrect: %v",
+			err,
+		)
 	}
 
 	weakTokenSubject, err := weakToken.Claims.GetSubject()
 	if err != nil {
-		return nil, eris.Errorf("weak token was missing subject claim, credentials probably incorrect: %v", err)
+		return nil, steamid.SteamID{}, eris.Errorf(
+			"weak token was missing subject claim, credentials probably incorrect: %v",
+			err,
+		)
 	}
 
 	steamID, err := steamid.ParseSteamID64(weakTokenSubject)
 	if err != nil {
-		return nil, eris.Errorf("weak token Sub returned invalid steamid64: %v", err)
+		return nil, steamid.SteamID{}, eris.Errorf("weak token Sub returned invalid steamid64: %v", err)
+	}
+
+	switch guardType {
+	case auth.DeviceCodeGuardType:
+		err = submitDeviceCode(
+			ctx,
+			authClient,
+			twoFactorClient,
+			options.AccountState.totpState,
+			sessionResponse.GetClientId(),
+			steamID,
+		)
+		if err != nil {
+			return nil, steamid.SteamID{}, err
+		}
+	case auth.EmailCodeGuardType:
+		code, err := options.GuardCodeFunc(ctx, guardType, associatedMessage)
+		if err != nil {
+			return nil, steamid.SteamID{}, eris.Wrap(err, "GuardCodeFunc failed")
+		}
+
+		err = authClient.SubmitSteamGuardCode(ctx, *sessionResponse.ClientId, steamID, code, guardType)
+		if err != nil {
+			return nil, steamid.SteamID{}, eris.Wrap(err, "error submitting email code")
+		}
+	}
+
+	return sessionResponse, steamID, nil
+}
+
+// ChallengeUrlFunc receives the URL that must be rendered as a QR code and scanned with the Steam mobile app to
+// approve a login. It is called again whenever Steam rotates the challenge.
+type ChallengeUrlFunc func(challengeUrl string)
+
+// AuthenticateViaQR logs in without credentials. The challenge URL is handed to challengeFunc, and the auth session
+// is polled until the login is approved from the Steam mobile app or ctx is cancelled.
+func AuthenticateViaQR(ctx context.Context, options Options, challengeFunc ChallengeUrlFunc) (*WebSession, error) {
+	if challengeFunc == nil {
+		return nil, errors.New("challengeFunc is required")
 	}
 
-	code, err := options.AccountState.totpState.GenerateTotpCode("conf", totp.Time(0))
+	webTransport, authClient, twoFactorClient, err := newSessionClients(ctx, options)
 	if err != nil {
-		return nil, eris.Errorf("error generating totp code failed: %v", err)
+		return nil, err
 	}
 
-	err = authClient.SubmitSteamGuardCode(ctx, *sessionResponse.ClientId, steamID, code)
+	sessionResponse, err := authClient.StartSessionViaQR(ctx, options.deviceProfile().deviceDetails())
 	if err != nil {
-		return nil, eris.Errorf("error submitting totp code: %v", err)
+		return nil, eris.Wrap(err, "StartSessionViaQR failed")
 	}
 
-	mobileConfClient, err := mobileconf.NewClient(
-		options.AccountState.totpState,
-		steamID,
-		twoFactorClient,
-		webTransport,
-	)
+	challengeFunc(sessionResponse.GetChallengeUrl())
+
+	clientId := sessionResponse.GetClientId()
+	pollInterval := time.Duration(float64(sessionResponse.GetInterval()) * float64(time.Second))
+	if pollInterval <= 0 {
+		pollInterval = 5 * time.Second
+	}
+
+	var pollResponse *steamproto.CAuthentication_PollAuthSessionStatus_Response
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, eris.Errorf("QR login was not approved: %v", ctx.Err())
+		case <-time.After(pollInterval):
+		}
+
+		pollResponse, err = authClient.PollSessionStatus(ctx, clientId, sessionResponse.GetRequestId())
+		if err != nil {
+			return nil, eris.Wrap(err, "PollSessionStatus failed")
+		}
+
+		if pollResponse.NewClientId != nil {
+			clientId = pollResponse.GetNewClientId()
+		}
+
+		if len(pollResponse.GetNewChallengeUrl()) != 0 {
+			challengeFunc(pollResponse.GetNewChallengeUrl())
+		}
+
+		if len(pollResponse.GetRefreshToken()) != 0 {
+			break
+		}
+	}
+
+	refreshJwt, err := auth.DecodeSimpleJwt(pollResponse.GetRefreshToken())
+	if err != nil {
+		return nil, eris.Errorf("refresh token was invalid JWT: %v", err)
+	}
+
+	steamID, err := steamid.ParseSteamID64(refreshJwt.Sub)
+	if err != nil {
+		return nil, eris.Errorf("refresh token Sub returned invalid steamid64: %v", err)
+	}
+
+	webSession, err := newWebSession(options, webTransport, authClient, twoFactorClient, steamID)
+	if err != nil {
+		return nil, err
+	}
+
+	webSession.clientId = clientId
+	webSession.requestId = sessionResponse.GetRequestId()
+	err = webSession.setTokens(ctx, pollResponse.GetAccessToken(), pollResponse.GetRefreshToken())
+	if err != nil {
+		return nil, err
+	}
+
+	return webSession, nil
+}
+
+// Resume restores a WebSession from a refresh token issued by an earlier login, without sending credentials or a
+// Steam Guard code. A fresh access token is generated from the refresh token and used to set the web cookies.
+func Resume(ctx context.Context, options Options, refreshToken string) (*WebSession, error) {
+	refreshTokenJwt, _, err := jwt.NewParser().ParseUnverified(refreshToken, jwt.MapClaims{})
+	if err != nil {
+		return nil, eris.Errorf("refresh token was invalid JWT: %v", err)
+	}
+
+	expirationTime, err := refreshTokenJwt.Claims.GetExpirationTime()
+	if err != nil || expirationTime == nil {
+		return nil, eris.Errorf("refresh token was missing expiration claim: %v", err)
+	}
+
+	if !time.Now().Before(expirationTime.Time) {
+		return nil, eris.Errorf("refresh token expired at %v", expirationTime.Time)
+	}
+
+	refreshTokenSubject, err := refreshTokenJwt.Claims.GetSubject()
 	if err != nil {
-		return nil, eris.Errorf("mobileconf.NewTransport failed: %v", err)
+		return nil, eris.Errorf("refresh token was missing subject claim: %v", err)
+	}
+
+	steamID, err := steamid.ParseSteamID64(refreshTokenSubject)
+	if err != nil {
+		return nil, eris.Errorf("refresh token Sub returned invalid steamid64: %v", err)
+	}
+
+	if !steamID.IsValidIndividual() {
+		return nil, eris.Errorf("refresh token Sub is not valid individual: %v", steamID.String())
+	}
+
+	webTransport, authClient, twoFactorClient, err := newSessionClients(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	webSession, err := newWebSession(options, webTransport, authClient, twoFactorClient, steamID)
+	if err != nil {
+		return nil, err
+	}
+
+	// with no access token given, setTokens generates one from the refresh token
+	err = webSession.setTokens(ctx, "", refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return webSession, nil
+}
+
+// newSessionClients creates the transport shared by a WebSession's api clients, along with the auth and two-factor
+// clients needed to log in, and aligns with Steam's clock.
+func newSessionClients(
+	ctx context.Context,
+	options Options,
+) (*api.HttpTransport, *auth.Client, *twofactor.Client, error) {
+	webTransport := api.NewTransport(options.HttpTransportOptions)
+	authClient := &auth.Client{
+		Transport: webTransport,
+	}
+	twoFactorClient := &twofactor.Client{
+		Transport: webTransport,
+	}
+
+	alignErr := twoFactorClient.AlignTime(ctx)
+	if alignErr != nil {
+		return nil, nil, nil, eris.Wrap(alignErr, "twoFactorClient.AlignTime() failed")
+	}
+
+	return webTransport, authClient, twoFactorClient, nil
+}
+
+// newWebSession creates the WebSession and the api clients that share its transport. Tokens and cookies are not
+// populated.
+func newWebSession(
+	options Options,
+	webTransport *api.HttpTransport,
+	authClient *auth.Client,
+	twoFactorClient *twofactor.Client,
+	steamID steamid.SteamID,
+) (*WebSession, error) {
+	deviceProfile := options.deviceProfile()
+
+	var mobileConfClient *mobileconf.Client
+	if options.AccountState != nil {
+		var err error
+		mobileConfClient, err = mobileconf.NewClient(
+			options.AccountState.totpState,
+			steamID,
+			deviceProfile.deviceId(options.AccountState, steamID),
+			twoFactorClient,
+			webTransport,
+		)
+		if err != nil {
+			return nil, eris.Errorf("mobileconf.NewTransport failed: %v", err)
+		}
+	}
+
+	econClient := &econ.Client{
+		Transport: webTransport,
 	}
 
 	webSession := &WebSession{
@@ -185,70 +429,218 @@ func Authenticate(ctx context.Context, options Options) (*WebSession, error) {
 		transport:        webTransport,
 		authClient:       authClient,
 		mobileConfClient: mobileConfClient,
+		econClient:       econClient,
 		tf2EconClient: &tf2econ.Client{
 			Transport: webTransport,
 		},
 		tradeOfferClient: &tradeoffer.Client{
 			Transport:     webTransport,
 			SessionIdFunc: GetSessionId,
+			Econ:          econClient,
 		},
 		twoFactorClient: twoFactorClient,
 		communityClient: &community.Client{
 			Transport: webTransport,
 		},
-		clientId:        *sessionResponse.ClientId,
-		requestId:       sessionResponse.RequestId,
-		steamId:         steamID,
-		refreshInterval: int(*sessionResponse.Interval),
+		steamId:                 steamID,
+		deviceProfile:           deviceProfile,
+		guardCodeFunc:           options.GuardCodeFunc,
+		refreshTokenRenewedFunc: options.RefreshTokenRenewedFunc,
 	}
 
-	err = webSession.pollSession(ctx)
+	if mobileConfClient != nil {
+		mobileConfClient.ReauthFunc = webSession.reauthenticate
+		// resolving the names of trade items in confirmation details needs the Web API
+		if len(options.WebApiKey) != 0 {
+			mobileConfClient.Econ = econClient
+		}
+	}
+
+	return webSession, nil
+}
+
+// reauthenticate restores the session after Steam reports that it has expired. The tokens are renewed from the
+// refresh token, and when that fails, e.g. because the refresh token was revoked, the account logs in again if
+// AccountState has its credentials. Callers that find the session expired at the same time share one restore.
+func (w *WebSession) reauthenticate(ctx context.Context) error {
+	expiredAccessToken := w.AccessToken()
+
+	w.reauthMutex.Lock()
+	defer w.reauthMutex.Unlock()
+
+	// the tokens were replaced while waiting, most likely by a caller that saw the same expired session
+	if w.AccessToken() != expiredAccessToken {
+		return nil
+	}
+
+	renewErr := w.RenewTokens(ctx)
+	if renewErr == nil {
+		return nil
+	}
+
+	// a session that was logged out on purpose must not be logged in again behind the caller's back
+	if errors.Is(renewErr, LoggedOutError) || w.state == nil || len(w.state.password) == 0 {
+		return eris.Wrap(renewErr, "RenewTokens failed")
+	}
+
+	log.Printf("Error renewing session tokens, logging in again: %v", renewErr)
+
+	options := Options{
+		AccountState:  w.state,
+		GuardCodeFunc: w.guardCodeFunc,
+		DeviceProfile: &w.deviceProfile,
+	}
+	sessionResponse, _, err := startCredentialSession(ctx, options, w.authClient, w.twoFactorClient)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	// N.B. we need a refresh token in order to get an access token, which we need in order to create the
-	// steamLoginSecure web cookie
-	if len(webSession.refreshToken) == 0 {
-		return nil, eris.Errorf("no refresh token found in poll response")
+	w.mutex.Lock()
+	w.clientId = sessionResponse.GetClientId()
+	w.requestId = sessionResponse.GetRequestId()
+	w.mutex.Unlock()
+
+	err = w.pollSession(ctx)
+	if err != nil {
+		return err
 	}
 
-	return webSession, nil
+	// logging in again issued a new refresh token, and any stored copy of the old one no longer works
+	if w.refreshTokenRenewedFunc != nil {
+		w.refreshTokenRenewedFunc(w.RefreshToken())
+	}
+
+	return nil
+}
+
+// submitDeviceCode submits the Steam Guard code for the current time window. When Steam rejects it, the code for the
+// adjacent window nearest to the current time is submitted once, as Steam's clock may already be in, or still be in,
+// that window.
+func submitDeviceCode(
+	ctx context.Context,
+	authClient *auth.Client,
+	twoFactorClient *twofactor.Client,
+	totpState *totp.State,
+	clientID uint64,
+	steamID steamid.SteamID,
+) error {
+	steamTime, err := twoFactorClient.SteamTime()
+	if err != nil {
+		return err
+	}
+
+	previous, current, next, err := totpState.GenerateAdjacentCodes(steamTime)
+	if err != nil {
+		return eris.Errorf("error generating totp code failed: %v", err)
+	}
+
+	err = authClient.SubmitSteamGuardCode(ctx, clientID, steamID, current.Code, auth.DeviceCodeGuardType)
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, auth.TwoFactorCodeMismatchError) {
+		return eris.Wrap(err, "error submitting totp code")
+	}
+
+	adjacent := next
+	if steamTime.Sub(current.ValidFrom) < totp.CodePeriod/2 {
+		adjacent = previous
+	}
+
+	err = authClient.SubmitSteamGuardCode(ctx, clientID, steamID, adjacent.Code, auth.DeviceCodeGuardType)
+	if err != nil {
+		return eris.Wrap(err, "error submitting totp code for adjacent time window")
+	}
+
+	return nil
 }
 
+// selectGuardType picks the Steam Guard confirmation we are able to complete from the ones Steam allows for this
+// login attempt. Device codes are preferred when the account has a shared secret, as they need no outside input.
+func selectGuardType(
+	allowedConfirmations []*steamproto.CAuthentication_AllowedConfirmation,
+	options Options,
+) (auth.GuardType, string, error) {
+	var emailCodeMessage string
+	hasDeviceCodeType := false
+	hasEmailCodeType := false
+	hasNoneType := false
+	for _, allowedConfirmation := range allowedConfirmations {
+		switch allowedConfirmation.GetConfirmationType() {
+		case steamproto.EAuthSessionGuardType_k_EAuthSessionGuardType_DeviceCode:
+			hasDeviceCodeType = true
+		case steamproto.EAuthSessionGuardType_k_EAuthSessionGuardType_EmailCode:
+			hasEmailCodeType = true
+			emailCodeMessage = allowedConfirmation.GetAssociatedMessage()
+		case steamproto.EAuthSessionGuardType_k_EAuthSessionGuardType_None:
+			hasNoneType = true
+		}
+	}
+
+	switch {
+	case hasNoneType:
+		return auth.NoneGuardType, "", nil
+	case hasDeviceCodeType && options.AccountState.totpState.HasSharedSecret():
+		return auth.DeviceCodeGuardType, "", nil
+	case hasEmailCodeType && options.GuardCodeFunc != nil:
+		return auth.EmailCodeGuardType, emailCodeMessage, nil
+	case hasDeviceCodeType:
+		return auth.UnknownGuardType, "", eris.Errorf("DeviceCode auth requires a shared secret in AccountState")
+	case hasEmailCodeType:
+		return auth.UnknownGuardType, "", eris.Errorf("EmailCode auth requires a GuardCodeFunc in Options")
+	}
+
+	return auth.UnknownGuardType, "", eris.Errorf("no supported auth in list of allowed confirmations")
+}
+
+// pollSession polls the auth session started by a credential login and stores the tokens it issued.
 func (w *WebSession) pollSession(ctx context.Context) error {
-	pollResponse, err := w.authClient.PollSessionStatus(ctx, w.clientId, w.requestId)
+	w.mutex.RLock()
+	clientId := w.clientId
+	requestId := w.requestId
+	w.mutex.RUnlock()
+
+	pollResponse, err := w.authClient.PollSessionStatus(ctx, clientId, requestId)
 	if err != nil {
-		return eris.Errorf("PollSessionStatus failed: %v", err)
+		return eris.Wrap(err, "PollSessionStatus failed")
 	}
 
 	if pollResponse.NewClientId != nil {
-		w.clientId = *pollResponse.NewClientId
+		w.mutex.Lock()
+		w.clientId = pollResponse.GetNewClientId()
+		w.mutex.Unlock()
 	}
 
-	// only attempt to refresh if a refresh token was given to us
+	// N.B. we need a refresh token in order to get an access token, which we need in order to create the
+	// steamLoginSecure web cookie
 	if len(pollResponse.GetRefreshToken()) == 0 {
-		return nil
+		return eris.Errorf("no refresh token found in poll response")
 	}
 
-	oldRefreshToken := w.refreshToken
-
 	// TODO: do we need to update state.accountName with pollResponse.Response.AccountName?
-	w.accessToken = pollResponse.GetAccessToken()
-	w.refreshToken = pollResponse.GetRefreshToken()
-	if len(w.accessToken) == 0 {
+	return w.setTokens(ctx, pollResponse.GetAccessToken(), pollResponse.GetRefreshToken())
+}
+
+// setTokens stores a newly issued token pair, generating the access token if Steam didn't issue one, and
+// refreshes the web cookies when the refresh token changed.
+func (w *WebSession) setTokens(ctx context.Context, accessToken string, refreshToken string) error {
+	if len(accessToken) == 0 {
 		// under some circumstances, the access token may not be issued by steam when polling login. We may need to
 		// establish the access token ourselves.
-		accessTokenResponse, accessTokenErr := w.authClient.GenerateAccessTokenForApp(ctx, w.refreshToken, false)
+		accessTokenResponse, accessTokenErr := w.authClient.GenerateAccessTokenForApp(ctx, refreshToken, false)
 		if accessTokenErr != nil {
-			return eris.Errorf("GenerateAccessTokenForApp failed: %v", accessTokenErr)
+			return eris.Wrap(accessTokenErr, "GenerateAccessTokenForApp failed")
 		}
 
-		w.accessToken = accessTokenResponse.GetAccessToken()
-		w.refreshToken = accessTokenResponse.GetRefreshToken()
+		accessToken = accessTokenResponse.GetAccessToken()
+		// steam only issues a new refresh token when renewal was requested
+		if len(accessTokenResponse.GetRefreshToken()) != 0 {
+			refreshToken = accessTokenResponse.GetRefreshToken()
+		}
 	}
 
-	refreshTokenJwt, _, err := jwt.NewParser().ParseUnverified(w.refreshToken, jwt.MapClaims{})
+	refreshTokenJwt, _, err := jwt.NewParser().ParseUnverified(refreshToken, jwt.MapClaims{})
 	if err != nil {
 		return eris.Errorf("refresh token was invalid JWT: %v", err)
 	}
@@ -257,19 +649,27 @@ func (w *WebSession) pollSession(ctx context.Context) error {
 		return eris.Errorf("refresh token was missing expiration claim: %v", err)
 	}
 
+	w.mutex.Lock()
+	oldRefreshToken := w.refreshToken
+	w.accessToken = accessToken
+	w.refreshToken = refreshToken
 	w.jwt = refreshTokenJwt
+	w.mutex.Unlock()
 
-	if oldRefreshToken != w.refreshToken {
-		err = w.finalizeLogin()
+	if oldRefreshToken != refreshToken {
+		err = w.finalizeLogin(ctx, refreshToken)
 		if err != nil {
-			return eris.Errorf("finalizeLogin failed: %v", err)
+			return eris.Wrap(err, "finalizeLogin failed")
 		}
 	}
 
 	return nil
 }
 
-func (w *WebSession) finalizeLogin() error {
+// finalizeLogin creates a new sessionid and exchanges the refresh token for the steamLoginSecure cookie of every
+// Steam site, following the transfers returned by login.steampowered.com/jwt/finalizelogin. Sessions that weren't
+// logged in as a WebBrowser fall back to setLoginCookie when the transfers fail.
+func (w *WebSession) finalizeLogin(ctx context.Context, refreshToken string) error {
 	sessionIdBuffer := [12]byte{}
 	_, err := rand.Read(sessionIdBuffer[:])
 	if err != nil {
@@ -279,37 +679,105 @@ func (w *WebSession) finalizeLogin() error {
 	sessionIdBytes := make([]byte, hex.EncodedLen(len(sessionIdBuffer)))
 	_ = hex.Encode(sessionIdBytes, sessionIdBuffer[:])
 
-	steamLoginSecure := fmt.Sprintf("%s||%s", w.steamId.String(), w.accessToken)
-	cookieUrl := &url.URL{Scheme: "https", Host: "steamcommunity.com", Path: "/"}
-	w.transport.CookieJar().SetCookies(cookieUrl, []*http.Cookie{
-		{
-			Name:  "sessionid",
-			Value: string(sessionIdBytes),
-		},
-		{
-			Name:  "steamLoginSecure",
-			Value: url.QueryEscape(steamLoginSecure),
-		},
-	})
+	for _, host := range webHosts {
+		cookieUrl := &url.URL{Scheme: "https", Host: host, Path: "/"}
+		w.transport.CookieJar().SetCookies(cookieUrl, []*http.Cookie{
+			{
+				Name:  "sessionid",
+				Value: string(sessionIdBytes),
+			},
+		})
+	}
 
-	// TODO: do i need to query finalizelogin? https://github.com/DoctorMcKay/node-steam-session/blob/811dadd2bfcc11de7861fff7442cb4a44ab61955/src/LoginSession.ts#L819-L835
+	err = w.transferLogin(ctx, refreshToken, string(sessionIdBytes))
+	if err != nil {
+		if w.deviceProfile.PlatformType == steamproto.EAuthTokenPlatformType_k_EAuthTokenPlatformType_WebBrowser {
+			return err
+		}
+
+		// tokens issued to the mobile app and steam client are accepted as the steamLoginSecure cookie as-is, so the
+		// session is still usable without the transfers
+		log.Printf("finalizelogin failed, using access token as login cookie: %v", err)
+
+		w.mutex.Lock()
+		w.setLoginCookie()
+		w.mutex.Unlock()
+	}
 
 	return nil
 }
 
+// transferLogin calls finalizelogin and follows every transfer it returns.
+func (w *WebSession) transferLogin(ctx context.Context, refreshToken string, sessionId string) error {
+	finalizeResponse, err := w.authClient.FinalizeLogin(ctx, refreshToken, sessionId)
+	if err != nil {
+		return eris.Wrap(err, "finalizelogin request failed")
+	}
+
+	for _, transferInfo := range finalizeResponse.TransferInfo {
+		err = w.authClient.TransferLogin(ctx, transferInfo, w.steamId)
+		if err != nil {
+			return eris.Wrapf(err, "TransferLogin to %v failed", transferInfo.Url)
+		}
+	}
+
+	return nil
+}
+
+// setLoginCookie writes the steamLoginSecure cookie for the current access token on every Steam site, replacing
+// the cookies set by finalizeLogin. Callers must hold w.mutex.
+func (w *WebSession) setLoginCookie() {
+	steamLoginSecure := fmt.Sprintf("%s||%s", w.steamId.String(), w.accessToken)
+	for _, host := range webHosts {
+		cookieUrl := &url.URL{Scheme: "https", Host: host, Path: "/"}
+		w.transport.CookieJar().SetCookies(cookieUrl, []*http.Cookie{
+			{
+				Name:  "steamLoginSecure",
+				Value: url.QueryEscape(steamLoginSecure),
+			},
+		})
+	}
+}
+
+// BeginPolling renews the session's tokens in the background until ctx is cancelled. The access token is renewed
+// ahead of its expiry, and the refresh token is rotated whenever Steam allows it. See RenewTokens. Steam's clock is
+// also periodically re-aligned, keeping generated codes valid.
+// Polling stops when the session is logged out, or when BeginPolling is called again.
 func (w *WebSession) BeginPolling(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	w.mutex.Lock()
+	if w.stopPolling != nil {
+		w.stopPolling()
+	}
+	w.stopPolling = cancel
+	w.mutex.Unlock()
+
+	w.twoFactorClient.BeginAligning(ctx, twofactor.DefaultAlignInterval)
+
 	go func() {
-		ticker := time.Tick(time.Duration(w.refreshInterval) * time.Second)
-		for range ticker {
+		for {
+			renewAt, err := w.nextRenewal()
+			if err != nil {
+				log.Printf("Error scheduling session token renewal, retrying in %v: %v", renewalRetryInterval, err)
+				renewAt = time.Now().Add(renewalRetryInterval)
+			}
+
 			select {
 			case <-ctx.Done():
 				return
-			default:
+			case <-time.After(time.Until(renewAt)):
 			}
 
-			err := w.pollSession(ctx)
+			err = w.RenewTokens(ctx)
 			if err != nil {
-				log.Printf("Error polling session: %v", err)
+				log.Printf("Error renewing session tokens: %v", err)
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(renewalRetryInterval):
+				}
 			}
 		}
 	}()
@@ -319,6 +787,37 @@ func (w *WebSession) SteamId() steamid.SteamID {
 	return w.steamId
 }
 
+// RefreshToken returns the session's current refresh token, which can be stored and later passed to Resume.
+func (w *WebSession) RefreshToken() string {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return w.refreshToken
+}
+
+// AccessToken returns the session's current access token.
+func (w *WebSession) AccessToken() string {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return w.accessToken
+}
+
+// webHosts are the Steam sites a WebSession is logged in to.
+var webHosts = []string{
+	"steamcommunity.com",
+	"store.steampowered.com",
+	"help.steampowered.com",
+	"checkout.steampowered.com",
+}
+
+// cookieHosts are the Steam sites a WebSession may hold cookies for.
+var cookieHosts = []string{
+	"steamcommunity.com",
+	"store.steampowered.com",
+	"help.steampowered.com",
+	"checkout.steampowered.com",
+	"login.steampowered.com",
+}
+
 func GetSessionId(transport api.Transport) (string, error) {
 	steamUrl := &url.URL{Scheme: "https", Host: "steamcommunity.com", Path: "/"}
 	steamCookies := transport.CookieJar().Cookies(steamUrl)
@@ -344,6 +843,10 @@ func (w *WebSession) EconClient() econ.Api {
 }
 
 func (w *WebSession) MobileConfClient() mobileconf.Api {
+	// avoid returning a non-nil interface wrapping a nil client for sessions without an AccountState
+	if w.mobileConfClient == nil {
+		return nil
+	}
 	return w.mobileConfClient
 }
 
@@ -0,0 +1,156 @@
+package steam
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/escrow-tf/steam/steamid"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rotisserie/eris"
+)
+
+// SessionSnapshotVersion is the version of SessionSnapshot written by WebSession.Snapshot. RestoreSession rejects
+// snapshots with any other version.
+const SessionSnapshotVersion = 1
+
+// SessionSnapshot holds everything needed to rebuild a working WebSession in another process. It contains the
+// session's tokens and cookies, so it must be stored as securely as the account's credentials.
+type SessionSnapshot struct {
+	Version      int              `json:"version"`
+	SteamId      string           `json:"steam_id"`
+	ClientId     uint64           `json:"client_id,string"`
+	RefreshToken string           `json:"refresh_token"`
+	AccessToken  string           `json:"access_token"`
+	Cookies      []SnapshotCookie `json:"cookies"`
+}
+
+// SnapshotCookie is a cookie held for Host, with the attributes it was set with.
+type SnapshotCookie struct {
+	Host     string        `json:"host"`
+	Name     string        `json:"name"`
+	Value    string        `json:"value"`
+	Path     string        `json:"path,omitempty"`
+	Domain   string        `json:"domain,omitempty"`
+	Expires  time.Time     `json:"expires,omitzero"`
+	Secure   bool          `json:"secure,omitempty"`
+	HttpOnly bool          `json:"http_only,omitempty"`
+	SameSite http.SameSite `json:"same_site,omitempty"`
+}
+
+// Snapshot captures the session's tokens and the cookies it holds for Steam's sites.
+func (w *WebSession) Snapshot() SessionSnapshot {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	snapshot := SessionSnapshot{
+		Version:      SessionSnapshotVersion,
+		SteamId:      w.steamId.String(),
+		ClientId:     w.clientId,
+		RefreshToken: w.refreshToken,
+		AccessToken:  w.accessToken,
+	}
+
+	for _, host := range cookieHosts {
+		cookieUrl := &url.URL{Scheme: "https", Host: host, Path: "/"}
+		for _, cookie := range w.transport.Cookies(cookieUrl) {
+			snapshot.Cookies = append(snapshot.Cookies, SnapshotCookie{
+				Host:     host,
+				Name:     cookie.Name,
+				Value:    cookie.Value,
+				Path:     cookie.Path,
+				Domain:   cookie.Domain,
+				Expires:  cookie.Expires,
+				Secure:   cookie.Secure,
+				HttpOnly: cookie.HttpOnly,
+				SameSite: cookie.SameSite,
+			})
+		}
+	}
+
+	return snapshot
+}
+
+// RestoreSession rebuilds a WebSession from a SessionSnapshot without logging in again.
+func RestoreSession(ctx context.Context, options Options, snapshot SessionSnapshot) (*WebSession, error) {
+	steamID, refreshTokenJwt, err := parseSnapshot(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	webTransport, authClient, twoFactorClient, err := newSessionClients(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	webSession, err := newWebSession(options, webTransport, authClient, twoFactorClient, steamID)
+	if err != nil {
+		return nil, err
+	}
+
+	webSession.applySnapshot(snapshot, refreshTokenJwt)
+	return webSession, nil
+}
+
+// parseSnapshot checks that the snapshot can be restored, returning its SteamID and decoded refresh token.
+func parseSnapshot(snapshot SessionSnapshot) (steamid.SteamID, *jwt.Token, error) {
+	if snapshot.Version != SessionSnapshotVersion {
+		return steamid.SteamID{}, nil, eris.Errorf("unsupported session snapshot version %d", snapshot.Version)
+	}
+
+	steamID, err := steamid.ParseSteamID64(snapshot.SteamId)
+	if err != nil {
+		return steamid.SteamID{}, nil, eris.Errorf("snapshot contained invalid steamid64: %v", err)
+	}
+
+	refreshTokenJwt, _, err := jwt.NewParser().ParseUnverified(snapshot.RefreshToken, jwt.MapClaims{})
+	if err != nil {
+		return steamid.SteamID{}, nil, eris.Errorf("snapshot refresh token was invalid JWT: %v", err)
+	}
+
+	refreshTokenSubject, err := refreshTokenJwt.Claims.GetSubject()
+	if err != nil {
+		return steamid.SteamID{}, nil, eris.Errorf("snapshot refresh token was missing subject claim: %v", err)
+	}
+
+	if refreshTokenSubject != steamID.String() {
+		return steamid.SteamID{}, nil, eris.Errorf(
+			"snapshot steamid %v doesn't match its refresh token's subject %v",
+			steamID.String(),
+			refreshTokenSubject,
+		)
+	}
+
+	return steamID, refreshTokenJwt, nil
+}
+
+// applySnapshot sets the session's tokens and cookies from the snapshot.
+func (w *WebSession) applySnapshot(snapshot SessionSnapshot, refreshTokenJwt *jwt.Token) {
+	cookiesByHost := make(map[string][]*http.Cookie)
+	for _, cookie := range snapshot.Cookies {
+		cookiesByHost[cookie.Host] = append(cookiesByHost[cookie.Host], &http.Cookie{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Path:     cookie.Path,
+			Domain:   cookie.Domain,
+			Expires:  cookie.Expires,
+			Secure:   cookie.Secure,
+			HttpOnly: cookie.HttpOnly,
+			SameSite: cookie.SameSite,
+		})
+	}
+
+	for host, cookies := range cookiesByHost {
+		cookieUrl := &url.URL{Scheme: "https", Host: host, Path: "/"}
+		w.transport.CookieJar().SetCookies(cookieUrl, cookies)
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.clientId = snapshot.ClientId
+	w.refreshToken = snapshot.RefreshToken
+	w.accessToken = snapshot.AccessToken
+	w.jwt = refreshTokenJwt
+}
@@ -0,0 +1,145 @@
+package steam
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/escrow-tf/steam/api"
+	"github.com/escrow-tf/steam/api/auth"
+	"github.com/escrow-tf/steam/api/twofactor"
+	"github.com/escrow-tf/steam/steamid"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSnapshotSteamId = "76561198006409530"
+
+func newTestSnapshotSession(t *testing.T) *WebSession {
+	t.Helper()
+
+	steamID, err := steamid.ParseSteamID64(testSnapshotSteamId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transport := api.NewTransport(api.HttpTransportOptions{})
+	webSession, err := newWebSession(
+		Options{},
+		transport,
+		&auth.Client{Transport: transport},
+		&twofactor.Client{Transport: transport},
+		steamID,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return webSession
+}
+
+func testRefreshToken(t *testing.T, subject string) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": subject,
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	original := newTestSnapshotSession(t)
+
+	refreshToken := testRefreshToken(t, testSnapshotSteamId)
+	refreshTokenJwt, _, err := jwt.NewParser().ParseUnverified(refreshToken, jwt.MapClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original.clientId = 1234
+	original.refreshToken = refreshToken
+	original.accessToken = "access"
+	original.jwt = refreshTokenJwt
+
+	cookieUrl := &url.URL{Scheme: "https", Host: "steamcommunity.com", Path: "/"}
+	original.transport.CookieJar().SetCookies(cookieUrl, []*http.Cookie{
+		{
+			Name:     "steamLoginSecure",
+			Value:    "login",
+			Path:     "/",
+			Expires:  time.Unix(time.Now().Add(time.Hour).Unix(), 0),
+			Secure:   true,
+			HttpOnly: true,
+			SameSite: http.SameSiteNoneMode,
+		},
+	})
+
+	snapshotJson, err := json.Marshal(original.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var snapshot SessionSnapshot
+	err = json.Unmarshal(snapshotJson, &snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, restoredJwt, err := parseSnapshot(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored := newTestSnapshotSession(t)
+	restored.applySnapshot(snapshot, restoredJwt)
+
+	expected := original.Snapshot()
+	actual := restored.Snapshot()
+	if actual.ClientId != expected.ClientId ||
+		actual.RefreshToken != expected.RefreshToken ||
+		actual.AccessToken != expected.AccessToken {
+		t.Errorf("restored tokens %+v, expected %+v", actual, expected)
+	}
+
+	if len(actual.Cookies) != len(expected.Cookies) {
+		t.Fatalf("restored cookies %+v, expected %+v", actual.Cookies, expected.Cookies)
+	}
+
+	for i, cookie := range actual.Cookies {
+		expectedCookie := expected.Cookies[i]
+		expires := cookie.Expires
+		cookie.Expires, expectedCookie.Expires = time.Time{}, time.Time{}
+		if cookie != expectedCookie || !expires.Equal(expected.Cookies[i].Expires) {
+			t.Errorf("restored cookie %+v, expected %+v", actual.Cookies[i], expected.Cookies[i])
+		}
+	}
+
+	var found bool
+	for _, cookie := range actual.Cookies {
+		if cookie.Name == "steamLoginSecure" {
+			found = cookie.Secure && cookie.HttpOnly && cookie.Path == "/" && !cookie.Expires.IsZero()
+		}
+	}
+	if !found {
+		t.Errorf("restored cookies %+v, expected steamLoginSecure with its attributes", actual.Cookies)
+	}
+}
+
+func TestParseSnapshotRejectsMismatchedSteamId(t *testing.T) {
+	snapshot := SessionSnapshot{
+		Version:      SessionSnapshotVersion,
+		SteamId:      testSnapshotSteamId,
+		RefreshToken: testRefreshToken(t, "76561197960287930"),
+	}
+
+	_, _, err := parseSnapshot(snapshot)
+	if err == nil {
+		t.Error("expected an error for a snapshot whose steamid doesn't match its refresh token")
+	}
+}
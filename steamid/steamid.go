@@ -89,6 +89,16 @@ func ParseSteamID64(s string) (steamID SteamID, err error) {
 	return steamID, nil
 }
 
+// individualAccountBase is the SteamID64 of the public individual desktop account with account id 0
+const individualAccountBase uint64 = 76561197960265728
+
+// FromIndividualAccountId returns the SteamID of the public individual account with the given account id, such as
+// the accountid of a trade offer's partner or a miniprofile id.
+func FromIndividualAccountId(accountId uint32) SteamID {
+	steamID, _ := ParseSteamID64(strconv.FormatUint(individualAccountBase+uint64(accountId), 10))
+	return steamID
+}
+
 func (id SteamID) String() string {
 	return id.original
 }
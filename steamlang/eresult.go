@@ -2,6 +2,7 @@
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -141,9 +142,28 @@ const (
 	PhoneNumberIsVOIPResult                       EResult = 127
 )
 
+// ResultError is returned when Steam responds with a non-OK EResult. Two ResultErrors match with errors.Is when
+// their Result is the same, so EResultError can be used as a comparison target.
+type ResultError struct {
+	Result  EResult
+	Message string
+}
+
+func (e *ResultError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("steam responded with non-OK Result: %v, %v", e.Result, e.Message)
+	}
+
+	return fmt.Sprintf("steam responded with non-OK Result: %v", e.Result)
+}
+
+func (e *ResultError) Is(target error) bool {
+	targetResultError, ok := target.(*ResultError)
+	return ok && targetResultError.Result == e.Result
+}
+
 func EResultError(e EResult) error {
-	// TODO: better way to represent eresult errors
-	return eris.Errorf("EResult: %d", e)
+	return &ResultError{Result: e}
 }
 
 func EnsureSuccessResponse(response *http.Response) error {
@@ -175,10 +195,10 @@ func EnsureEResultResponse(httpResponse *http.Response) error {
 				errorMessages[i] = errors.New(header)
 			}
 
-			return eris.Errorf("steam responded with non-OK Result: %v, %v", eResult, errors.Join(errorMessages...))
+			return &ResultError{Result: eResult, Message: errors.Join(errorMessages...).Error()}
 		}
 
-		return eris.Errorf("steam responded with non-OK Result: %v", eResult)
+		return &ResultError{Result: eResult}
 	}
 
 	return nil
@@ -3,6 +3,7 @@
 import (
 	"crypto/hmac"
 	"crypto/sha1"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/binary"
 	"fmt"
@@ -33,10 +34,72 @@ func NewState(sharedSecret string, identitySecret string) (*State, error) {
 	}, nil
 }
 
+// HasSharedSecret reports whether the State can generate Steam Guard codes. Accounts protected by email Steam
+// Guard have no shared secret.
+func (s State) HasSharedSecret() bool {
+	return len(s.sharedSecret) != 0
+}
+
+// SharedSecret returns the base64 encoded shared secret, as accepted by NewState.
+func (s State) SharedSecret() string {
+	return base64.StdEncoding.EncodeToString(s.sharedSecret)
+}
+
+// IdentitySecret returns the base64 encoded identity secret, as accepted by NewState.
+func (s State) IdentitySecret() string {
+	return base64.StdEncoding.EncodeToString(s.identitySecret)
+}
+
 func Time(offset int64) time.Time {
 	return time.Now().UTC().Add(time.Second * time.Duration(offset))
 }
 
+// CodePeriod is the length of the time window each Steam Guard code is valid for.
+const CodePeriod = 30 * time.Second
+
+// Code is a Steam Guard code along with the time window it is valid for.
+type Code struct {
+	Code      string
+	ValidFrom time.Time
+	ExpiresAt time.Time
+}
+
+// Remaining returns how long the code remains valid as of now, or 0 if it has expired.
+func (c Code) Remaining(now time.Time) time.Duration {
+	return max(c.ExpiresAt.Sub(now), 0)
+}
+
+// GenerateCode returns the Steam Guard code for the time window containing useTime. useTime should be Steam's time,
+// e.g. from twofactor.Client.SteamTime.
+func (s State) GenerateCode(useTime time.Time) (Code, error) {
+	validFrom := useTime.Truncate(CodePeriod)
+	code, err := s.GenerateTotpCode("", validFrom)
+	if err != nil {
+		return Code{}, err
+	}
+
+	return Code{
+		Code:      code,
+		ValidFrom: validFrom,
+		ExpiresAt: validFrom.Add(CodePeriod),
+	}, nil
+}
+
+// GenerateAdjacentCodes returns the codes for the time windows before, containing and after useTime. Steam may
+// accept an adjacent code when its clock and ours disagree around a window boundary.
+func (s State) GenerateAdjacentCodes(useTime time.Time) (previous Code, current Code, next Code, err error) {
+	if previous, err = s.GenerateCode(useTime.Add(-CodePeriod)); err != nil {
+		return
+	}
+	if current, err = s.GenerateCode(useTime); err != nil {
+		return
+	}
+	next, err = s.GenerateCode(useTime.Add(CodePeriod))
+	return
+}
+
+// GenerateTotpCode returns the Steam Guard code for the time window containing time. tag is unused; see also
+// GenerateCode, which includes the code's validity window.
 func (s State) GenerateTotpCode(tag string, time time.Time) (string, error) {
 	// Converting time for any reason
 	// 00 00 00 00 00 00 00 00
@@ -100,6 +163,30 @@ func (s State) GenerateConfirmationKey(useTime time.Time, tag []byte) (hashCode
 	return
 }
 
+// GenerateSessionSignature signs the approval or denial of a login attempt, as sent to
+// UpdateAuthSessionWithMobileConfirmation. The signed buffer is the little-endian version, client id and steamid.
+func (s State) GenerateSessionSignature(version int32, clientID uint64, steamID uint64) []byte {
+	buffer := make([]byte, 2+8+8)
+	binary.LittleEndian.PutUint16(buffer, uint16(version))
+	binary.LittleEndian.PutUint64(buffer[2:], clientID)
+	binary.LittleEndian.PutUint64(buffer[10:], steamID)
+
+	mac := hmac.New(sha256.New, s.sharedSecret)
+	mac.Write(buffer)
+	return mac.Sum(nil)
+}
+
+// GenerateTokenRevokeSignature signs the revocation of a refresh token by its id, as sent to RevokeRefreshToken.
+// The signed buffer is the little-endian token id.
+func (s State) GenerateTokenRevokeSignature(tokenID uint64) []byte {
+	buffer := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buffer, tokenID)
+
+	mac := hmac.New(sha256.New, s.sharedSecret)
+	mac.Write(buffer)
+	return mac.Sum(nil)
+}
+
 func GetDeviceId(steamID string) string {
 	checksum := sha1.Sum([]byte(steamID))
 	checksumBase64 := base64.StdEncoding.EncodeToString(checksum[:])
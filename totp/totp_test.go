@@ -21,3 +21,38 @@ func TestGenerateAuthCode(t *testing.T) {
 		t.Errorf("len(code)=%d, expected 5 digit code", len(code))
 	}
 }
+
+func TestGenerateAdjacentCodes(t *testing.T) {
+	//goland:noinspection SpellCheckingInspection
+	state, err := NewState("cnOgv/KdpLoP6Nbh0GMkXkPXALQ=", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	useTime := time.Unix(1700000020, 0)
+	previous, current, next, err := state.GenerateAdjacentCodes(useTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !current.ValidFrom.Equal(time.Unix(1700000010, 0)) || !current.ExpiresAt.Equal(time.Unix(1700000040, 0)) {
+		t.Errorf("current window=%v-%v, expected window starting at 1700000010", current.ValidFrom, current.ExpiresAt)
+	}
+
+	if !previous.ExpiresAt.Equal(current.ValidFrom) || !next.ValidFrom.Equal(current.ExpiresAt) {
+		t.Errorf("adjacent windows were not contiguous with the current window")
+	}
+
+	if remaining := current.Remaining(useTime.Add(15 * time.Second)); remaining != 5*time.Second {
+		t.Errorf("Remaining()=%v, expected 5s", remaining)
+	}
+
+	code, err := state.GenerateTotpCode("conf", useTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if code != current.Code {
+		t.Errorf("GenerateTotpCode()=%s, expected %s", code, current.Code)
+	}
+}